@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"word_counter/wordcount"
+)
+
+// benchFlagSet registers the flags the bench subcommand accepts: the
+// counting flags (so any pipeline configuration can be benchmarked)
+// plus the synthetic corpus parameters.
+func benchFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	commonFlags(fs)
+	countingFlags(fs)
+	fs.StringVar(&benchSize, "size", "64M", "total corpus size to generate")
+	fs.IntVar(&benchFiles, "files", 8, "number of files to spread the corpus across")
+	fs.IntVar(&benchVocab, "vocab", 50000, "vocabulary size of the synthetic corpus")
+	fs.Float64Var(&benchSkew, "skew", 1.2, "Zipf exponent of the word distribution (must be > 1)")
+	fs.Int64Var(&benchSeed, "seed", 1, "random seed; the same seed always generates the same corpus")
+	fs.IntVar(&benchRuns, "runs", 3, "how many times to run the pipeline over the corpus")
+	return fs
+}
+
+// runBench generates a reproducible synthetic corpus, runs the counting
+// pipeline over it, and reports throughput — the standard harness for
+// performance work, so numbers compare across changes and machines.
+func runBench(args []string) {
+	fs := benchFlagSet()
+	fs.Parse(args)
+	shutdown := setup(fs)
+	defer shutdown()
+
+	size, err := parseSize(benchSize)
+	if err != nil {
+		log.Fatalf("Invalid -size: %v", err)
+	}
+	dir, err := os.MkdirTemp("", "wordcounter-bench")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	genStart := time.Now()
+	files, err := wordcount.GenerateBenchCorpus(dir, benchFiles, size, benchVocab, benchSkew, benchSeed)
+	if err != nil {
+		log.Fatalf("Failed to generate corpus: %v", err)
+	}
+	fmt.Printf("Generated %d files, %s, vocab %d, skew %.2f, seed %d in %v\n",
+		len(files), benchSize, benchVocab, benchSkew, benchSeed, time.Since(genStart).Round(time.Millisecond))
+
+	opts := buildOptions(nil)
+	best := 0.0
+	for run := 1; run <= benchRuns; run++ {
+		start := time.Now()
+		result, err := wordcount.ProcessFilesContext(context.Background(), files, opts)
+		if err != nil {
+			log.Fatalf("Run %d failed: %v", run, err)
+		}
+		elapsed := time.Since(start)
+		var tokens int64
+		for _, c := range result.Counts {
+			tokens += int64(c)
+		}
+		mbps := float64(size) / (1 << 20) / elapsed.Seconds()
+		if mbps > best {
+			best = mbps
+		}
+		fmt.Printf("run %d/%d: %v  %.1f MB/s  %.0f words/s  (%d tokens, %d distinct)\n",
+			run, benchRuns, elapsed.Round(time.Millisecond), mbps,
+			float64(tokens)/elapsed.Seconds(), tokens, len(result.Counts))
+	}
+	fmt.Printf("best: %.1f MB/s\n", best)
+}
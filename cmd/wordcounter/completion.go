@@ -0,0 +1,124 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// completionCommand pairs a completable subcommand with its flag set
+// and the description its completions carry.
+type completionCommand struct {
+	name  string
+	desc  string
+	flags *flag.FlagSet
+}
+
+// completionCommands lists the completable subcommands in help order.
+// Both completion scripts are generated from this table and the live
+// flag registrations, so neither the subcommand lists nor the
+// per-command flag cases can drift from what the commands actually
+// accept.
+func completionCommands() []completionCommand {
+	return []completionCommand{
+		{"count", "count words in files, directories, archives, or stdin", countFlagSet()},
+		{"serve", "run the HTTP job server or an RPC counting worker", serveFlagSet()},
+		{"diff", "compare two corpora or saved listings by count change", diffFlagSet()},
+		{"merge", "combine saved count files into one aggregate", mergeFlagSet()},
+		{"bench", "generate a synthetic corpus and report pipeline throughput", benchFlagSet()},
+		{"profile", "compare saved profile bundles", profileFlagSet()},
+	}
+}
+
+// flagNames lists a command's flags with the leading dash.
+func flagNames(fs *flag.FlagSet) []string {
+	var names []string
+	fs.VisitAll(func(f *flag.Flag) { names = append(names, "-"+f.Name) })
+	return names
+}
+
+// runCompletion writes a completion script for the named shell to
+// stdout, for the user to source or install:
+//
+//	wordcounter completion bash > /etc/bash_completion.d/wordcounter
+//	wordcounter completion zsh  > "${fpath[1]}/_wordcounter"
+//	wordcounter completion fish > ~/.config/fish/completions/wordcounter.fish
+func runCompletion(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: wordcounter completion bash|zsh|fish")
+		os.Exit(2)
+	}
+	switch args[0] {
+	case "bash":
+		writeBashCompletion(os.Stdout)
+	case "zsh":
+		// zsh runs the bash script through bashcompinit, which keeps
+		// the two shells completing identically without a second
+		// hand-written script
+		fmt.Println("autoload -U +X bashcompinit && bashcompinit")
+		writeBashCompletion(os.Stdout)
+	case "fish":
+		writeFishCompletion(os.Stdout)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown shell %q. Valid options are: bash, zsh, fish\n", args[0])
+		os.Exit(2)
+	}
+}
+
+// writeBashCompletion emits a bash completion function: subcommands in
+// the first position, the active command's flags after a dash, and file
+// names everywhere else.
+func writeBashCompletion(w *os.File) {
+	cmds := completionCommands()
+	names := make([]string, 0, len(cmds)+3)
+	for _, c := range cmds {
+		names = append(names, c.name)
+	}
+	names = append(names, "completion", "version", "help")
+	fmt.Fprintf(w, `_wordcounter() {
+    local cur cmd flags
+    cur=${COMP_WORDS[COMP_CWORD]}
+    if [[ $COMP_CWORD -eq 1 && $cur != -* ]]; then
+        COMPREPLY=($(compgen -W %q -- "$cur"))
+        return
+    fi
+    cmd=${COMP_WORDS[1]}
+    case $cmd in
+`, strings.Join(names, " "))
+	for _, c := range cmds {
+		fmt.Fprintf(w, "        %s) flags=%q ;;\n", c.name, strings.Join(flagNames(c.flags), " "))
+	}
+	fmt.Fprintf(w, "        completion) COMPREPLY=($(compgen -W \"bash zsh fish\" -- \"$cur\")); return ;;\n")
+	// A bare invocation defaults to count, so its flags complete too
+	fmt.Fprintf(w, "        *) flags=%q ;;\n", strings.Join(flagNames(cmds[0].flags), " "))
+	fmt.Fprintln(w, `    esac
+    if [[ $cur == -* ]]; then
+        COMPREPLY=($(compgen -W "$flags" -- "$cur"))
+        return
+    fi
+    COMPREPLY=($(compgen -f -- "$cur"))
+}
+complete -o filenames -F _wordcounter wordcounter`)
+}
+
+// writeFishCompletion emits fish completions: one entry per subcommand
+// and one per flag, each carrying its usage string as the description.
+func writeFishCompletion(w *os.File) {
+	sub := func(name, desc string) {
+		fmt.Fprintf(w, "complete -c wordcounter -n __fish_use_subcommand -a %s -d %q\n", name, desc)
+	}
+	cmds := completionCommands()
+	for _, c := range cmds {
+		sub(c.name, c.desc)
+	}
+	sub("completion", "print a shell completion script")
+	sub("version", "print build information")
+	fmt.Fprintln(w, "complete -c wordcounter -n '__fish_seen_subcommand_from completion' -a 'bash zsh fish'")
+	for _, c := range cmds {
+		name := c.name
+		c.flags.VisitAll(func(f *flag.Flag) {
+			fmt.Fprintf(w, "complete -c wordcounter -n '__fish_seen_subcommand_from %s' -o %s -d %q\n", name, f.Name, f.Usage)
+		})
+	}
+}
@@ -0,0 +1,436 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"runtime/trace"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/profile"
+	log "github.com/sirupsen/logrus"
+
+	"word_counter/wordcount"
+)
+
+// countFlagSet registers the flags the count subcommand accepts. The
+// completion generator builds it too, so the scripts never drift from
+// the real flag set.
+func countFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("count", flag.ExitOnError)
+	commonFlags(fs)
+	countingFlags(fs)
+	outputFlags(fs)
+	fs.StringVar(&profileType, "profile", "", "type of profiling: cpu, mem, block, or trace")
+	fs.StringVar(&profileOut, "profile-out", "", "write the profile into this directory (a labeled bundle for profile diff) instead of a temp dir")
+	fs.BoolVar(&recursive, "r", false, "process directories recursively")
+	fs.BoolVar(&followLinks, "follow-symlinks", false, "resolve symlinks during recursive walks (cycles are detected and visited once)")
+	fs.BoolVar(&dedupe, "dedupe", false, "hash files and process identical content only once, reporting duplicates")
+	fs.StringVar(&includeGlob, "include", "", "only process files matching these comma-separated globs (e.g. '*.md,docs/**')")
+	fs.StringVar(&excludeGlob, "exclude", "", "skip files matching these comma-separated globs (e.g. 'vendor/**,*.min.js')")
+	fs.BoolVar(&perFile, "per-file", false, "report counts per input file in addition to the totals")
+	fs.StringVar(&sentiment, "sentiment", "", "score files against a sentiment lexicon: a word<TAB>score file (AFINN format), or \"builtin\"")
+	fs.BoolVar(&vocab, "vocab", false, "report per-file vocabulary statistics (types, tokens, type/token ratio)")
+	fs.BoolVar(&richness, "richness", false, "report hapax/dis legomena counts and richness measures (Herdan's C, Yule's K)")
+	fs.BoolVar(&tfidf, "tfidf", false, "report per-word document frequency and TF-IDF scores")
+	fs.BoolVar(&zipf, "zipf", false, "emit rank-frequency pairs with a fitted Zipf exponent instead of the count listing")
+	fs.BoolVar(&streamSort, "stream-sorted", false, "stream counts in word order via an external merge, never holding the full vocabulary in memory")
+	fs.StringVar(&coordinate, "coordinate", "", "shard files across these worker addresses (comma-separated) instead of counting locally")
+	fs.StringVar(&indexFile, "index", "", "persistent per-file count index; unchanged files reuse cached counts")
+	fs.StringVar(&checkpoint, "checkpoint", "", "persist progress to this file after each batch and resume from it on restart")
+	fs.BoolVar(&progress, "progress", false, "show a live progress line (files done, bytes read, ETA) on stderr")
+	fs.DurationVar(&timeout, "timeout", 0, "cancel the run after this long (e.g. 10m), reporting partial results")
+	fs.BoolVar(&strict, "strict", false, "exit nonzero when inputs are missing or unreadable (2 no inputs, 3 partial, 4 total failure)")
+	fs.StringVar(&watchDir, "watch", "", "watch this directory and keep the aggregate updated as files change")
+	fs.StringVar(&kafkaAddrs, "kafka-brokers", "", "consume a Kafka topic from these brokers (comma-separated) instead of reading files")
+	fs.StringVar(&kafkaTopic, "kafka-topic", "", "Kafka topic to consume (required with -kafka-brokers)")
+	fs.StringVar(&kafkaGroup, "kafka-group", "", "Kafka consumer group ID; commits offsets so restarts resume")
+	fs.DurationVar(&snapshotInt, "snapshot-interval", 0, "how often streaming modes report the running aggregate (default 10s)")
+	return fs
+}
+
+// runCount counts the named files (or stdin) and prints the results.
+// This is the default subcommand and carries the full flag set.
+func runCount(args []string) {
+	fs := countFlagSet()
+	fs.Parse(args)
+	shutdown := setup(fs)
+	defer shutdown()
+
+	fmt.Printf("Calculating each word ocurrence count..\n")
+
+	// Start profiling based on the profileType flag; -profile-out turns
+	// the run into a labeled bundle that profile diff can compare
+	var popts []func(*profile.Profile)
+	if profileOut != "" {
+		popts = append(popts, profile.ProfilePath(profileOut))
+	}
+	var profiler interface{ Stop() }
+	switch profileType {
+	case "cpu":
+		profiler = profile.Start(append(popts, profile.CPUProfile)...)
+	case "mem":
+		profiler = profile.Start(append(popts, profile.MemProfile)...)
+	case "block":
+		profiler = profile.Start(append(popts, profile.BlockProfile)...)
+	case "trace":
+		traceFile, err := os.Create("trace.out")
+		if err != nil {
+			log.Fatal("Could not create trace file: ", err)
+		}
+		defer traceFile.Close()
+		if err := trace.Start(traceFile); err != nil {
+			log.Fatal("Could not start trace: ", err)
+		}
+		defer trace.Stop() // Ensure tracing stops when the program exits
+	default:
+		if profileType != "" {
+			log.Warn("Invalid profile type. Valid options are: cpu, mem, block, trace")
+		}
+	}
+	defer stopProfiling(profiler)
+
+	// Process files; with no arguments, read from standard input
+	start := time.Now()
+	inputs := fs.Args()
+	if len(inputs) == 0 {
+		inputs = []string{"-"}
+	}
+	files := wordcount.ExpandArgs(inputs, recursive, followLinks)
+	files = wordcount.FilterFiles(files, splitGlobs(includeGlob), splitGlobs(excludeGlob))
+	if dedupe {
+		files = wordcount.DedupeFiles(files)
+	}
+	if len(files) == 0 {
+		log.Error("No files to process")
+		if strict {
+			stopProfiling(profiler)
+			os.Exit(exitNoInputs)
+		}
+		return
+	}
+
+	// wc statistics are a different report entirely; compute them with
+	// the parallel pool and skip the counting pipeline
+	if mode == wordcount.ModeWC {
+		runStats(files, start)
+		return
+	}
+
+	// A first SIGINT/SIGTERM stops enqueuing and drains in-flight files
+	// so partial results are still reported; a second one kills the run
+	stop := make(chan struct{})
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigc
+		log.Warn("Interrupted; draining in-flight files, interrupt again to abort")
+		close(stop)
+		signal.Stop(sigc)
+	}()
+
+	opts := buildOptions(stop)
+
+	// Kafka mode consumes a topic indefinitely, reprinting the running
+	// aggregate every snapshot interval until interrupted
+	if kafkaAddrs != "" {
+		if kafkaTopic == "" {
+			log.Fatal("-kafka-brokers requires -kafka-topic")
+		}
+		err := wordcount.ConsumeKafka(strings.Split(kafkaAddrs, ","), kafkaTopic, kafkaGroup, snapshotInt, opts, func(total map[string]int) {
+			fmt.Printf("== %s: %d distinct words ==\n", time.Now().Format(time.TimeOnly), len(total))
+			if topN > 0 || sortBy != "" || format != "" {
+				if err := wordcount.WriteCounts(os.Stdout, orderedCounts(total), format); err != nil {
+					log.Error(err)
+				}
+			}
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Watch mode maintains a continuously updated aggregate over a
+	// directory, reprinting totals after every change until interrupted
+	if watchDir != "" {
+		err := wordcount.Watch(watchDir, opts, func(total map[string]int) {
+			fmt.Printf("== %s: %d distinct words ==\n", time.Now().Format(time.TimeOnly), len(total))
+			if topN > 0 || sortBy != "" || format != "" {
+				if err := wordcount.WriteCounts(os.Stdout, orderedCounts(total), format); err != nil {
+					log.Error(err)
+				}
+			}
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Streaming mode never builds the final map: reducers spill sorted
+	// runs and an external k-way merge emits each word exactly once
+	if streamSort {
+		fmt.Printf("%-10s%s\n%-10s%s\n", "Count", "Word", "-----", "----")
+		words := 0
+		err := wordcount.ProcessFilesSorted(files, opts, func(word string, count int) error {
+			words++
+			_, err := fmt.Printf("%-10v%s\n", count, word)
+			return err
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Processing took: %v\n Total words: %v\n", time.Since(start), words)
+		return
+	}
+
+	// Distributed mode ships shards to remote workers and reduces their
+	// partial maps; it yields totals only, so per-file reports stay local
+	var (
+		result *wordcount.Result
+		err    error
+	)
+	if indexFile != "" {
+		result, err = wordcount.ProcessFilesIndexed(files, opts, indexFile)
+	} else if checkpoint != "" {
+		var counts map[string]int
+		counts, err = wordcount.ProcessFilesCheckpointed(files, opts, checkpoint)
+		if counts != nil {
+			result = &wordcount.Result{Counts: counts}
+		}
+	} else if coordinate != "" {
+		var counts map[string]int
+		counts, err = wordcount.Coordinate(strings.Split(coordinate, ","), files, opts)
+		if counts != nil {
+			result = &wordcount.Result{Counts: counts}
+		}
+	} else {
+		// A -timeout bounds the whole run; expiry cancels workers and
+		// reducers and leaves a partial result worth reporting
+		ctx := context.Background()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		result, err = wordcount.ProcessFilesContext(ctx, files, opts)
+	}
+
+	// Per-file failures are warnings: the aggregate over the readable
+	// inputs is still worth reporting
+	inputErrs := wordcount.InputErrors(err)
+	for _, ie := range inputErrs {
+		log.Warnf("Skipped %s: %v", ie.Name, ie.Err)
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		log.Warnf("Run timed out after %v; results below are partial", timeout)
+	case err != nil && result == nil:
+		log.Fatal(err)
+	}
+	finalResult := result.Counts
+
+	// An interrupted run reports what it never got to, so the partial
+	// aggregate below can be interpreted (and the rest re-run) honestly
+	if len(result.Unprocessed) > 0 {
+		log.Warnf("Interrupted before processing %d of %d files:", len(result.Unprocessed), len(files))
+		for _, name := range result.Unprocessed {
+			fmt.Fprintf(os.Stderr, "  %s\n", name)
+		}
+	}
+
+	// Cardinality-only mode never counted anything: report the
+	// estimated distinct words per input and overall, with the sketch's
+	// expected error
+	if d := opts.Distinct; d != nil {
+		fmt.Printf("%12s  %s\n%12s  %s\n", "Distinct", "File", "--------", "----")
+		for _, fe := range d.Files() {
+			fmt.Printf("%12d  %s\n", fe.Distinct, fe.Name)
+		}
+		fmt.Printf("Processing took: %v\n Distinct words: %v (±%.1f%% expected)\n",
+			time.Since(start), d.Total(), d.StdError()*100)
+		return
+	}
+
+	// Approximate mode never built the exact maps: report the sketch's
+	// heavy hitters and its guarantee instead of the count listing
+	if s := opts.Sketch; s != nil {
+		if topN > 0 {
+			if err := wordcount.WriteCounts(os.Stdout, s.TopK(topN), format); err != nil {
+				log.Error(err)
+			}
+		}
+		eps, conf := s.ErrorBound()
+		tokens := s.Tokens()
+		fmt.Printf("Count-Min sketch: %dx%d cells (%.1f MB); estimates within %d of true counts with ≥%.1f%% confidence\n",
+			s.Width(), s.Depth(), float64(s.MemoryBytes())/(1<<20), int64(math.Ceil(eps*float64(tokens))), conf*100)
+		fmt.Printf("Processing took: %v\n Total tokens: %v (approximate mode)\n", time.Since(start), tokens)
+		return
+	}
+
+	// TF-IDF needs the per-file breakdown; report scores and skip the
+	// regular count listing
+	if tfidf {
+		scores := wordcount.ComputeTFIDF(result.Files)
+		if topN > 0 && topN < len(scores) {
+			scores = scores[:topN]
+		}
+		if err := wordcount.WriteTFIDF(os.Stdout, scores); err != nil {
+			log.Error(err)
+		}
+	}
+
+	// Print a per-file breakdown before the rolled-up totals
+	if perFile {
+		for _, fr := range result.Files {
+			fmt.Printf("\n== %s ==\n", fr.Name)
+			if err := wordcount.WriteCounts(os.Stdout, orderedCounts(fr.Counts), format); err != nil {
+				log.Error(err)
+			}
+		}
+		fmt.Printf("\n== total ==\n")
+	}
+
+	// Sentiment is scored from the retained counts, never during
+	// tokenization, so it composes with stemming and stopwords
+	if sentiment != "" {
+		lex := wordcount.BuiltinLexicon()
+		if sentiment != "builtin" {
+			var lerr error
+			if lex, lerr = wordcount.LoadLexicon(sentiment); lerr != nil {
+				log.Fatalf("Failed to load sentiment lexicon: %v", lerr)
+			}
+		}
+		if err := wordcount.WriteSentiment(os.Stdout, result.Files, finalResult, lex); err != nil {
+			log.Error(err)
+		}
+	}
+
+	// Vocabulary statistics ride on the per-file breakdown the same way
+	// TF-IDF does
+	if vocab {
+		if err := wordcount.WriteVocabStats(os.Stdout, result.Files); err != nil {
+			log.Error(err)
+		}
+	}
+
+	// Richness is a summary over the final map, so it composes with any
+	// listing rather than replacing one
+	if richness {
+		if err := wordcount.WriteRichness(os.Stdout, finalResult); err != nil {
+			log.Error(err)
+		}
+	}
+
+	// The Zipf export replaces the regular listing with rank-frequency
+	// pairs; the exponent is fitted over the whole vocabulary even when
+	// -top trims the rows
+	if zipf {
+		ranked := wordcount.SortCounts(finalResult, wordcount.SortByCount, true)
+		s, r2 := wordcount.ZipfExponent(ranked)
+		rows := ranked
+		if topN > 0 && topN < len(rows) {
+			rows = rows[:topN]
+		}
+		if err := wordcount.WriteZipf(os.Stdout, rows, format); err != nil {
+			log.Error(err)
+		}
+		fmt.Printf("Zipf exponent: %.3f (log-log R²: %.3f over %d ranks)\n", s, r2, len(ranked))
+	}
+
+	// The length histogram replaces the regular listing: its keys are
+	// numbers, and the report sorts and summarizes them itself
+	if mode == wordcount.ModeLengths {
+		if err := wordcount.WriteLengthReport(os.Stdout, finalResult); err != nil {
+			log.Error(err)
+		}
+	}
+
+	// Print the final word count results when a listing was requested
+	if mode != wordcount.ModeLengths && !tfidf && !zipf && (topN > 0 || sortBy != "" || format != "" || outputPath != "" || perFile || freqCols) {
+		counts := orderedCounts(finalResult)
+		if freqCols {
+			// Percentages are taken against the whole corpus even when
+			// -top trims the listing
+			var tokens int64
+			for _, c := range finalResult {
+				tokens += int64(c)
+			}
+			if outputPath != "" {
+				if err := wordcount.WriteFreqCountsFile(outputPath, counts, format, tokens); err != nil {
+					log.Errorf("Failed to write results to %s: %v", outputPath, err)
+				}
+			} else if err := wordcount.WriteFreqCounts(os.Stdout, counts, format, tokens); err != nil {
+				log.Error(err)
+			}
+		} else if outputPath != "" {
+			if err := wordcount.WriteCountsFile(outputPath, counts, format); err != nil {
+				log.Errorf("Failed to write results to %s: %v", outputPath, err)
+			}
+		} else if err := wordcount.WriteCounts(os.Stdout, counts, format); err != nil {
+			log.Error(err)
+		}
+	}
+	// A sink is written in addition to (not instead of) the listing, so
+	// a run can both print and persist
+	if outputSink != "" {
+		if err := wordcount.WriteSink(outputSink, result); err != nil {
+			log.Errorf("Failed to write results to %s: %v", outputSink, err)
+		}
+	}
+	fmt.Printf("Processing took: %v\n Total words: %v\n", time.Since(start), len(finalResult)) // Print elapsed time
+
+	// In strict mode the exit code distinguishes a clean run, a run that
+	// skipped some inputs, and a run where nothing was readable
+	if strict && len(inputErrs) > 0 {
+		stopProfiling(profiler)
+		if len(finalResult) == 0 {
+			os.Exit(exitFailure)
+		}
+		os.Exit(exitPartial)
+	}
+}
+
+// splitGlobs turns a comma-separated -include/-exclude value into a
+// pattern list, dropping empty entries.
+func splitGlobs(s string) []string {
+	var pats []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			pats = append(pats, p)
+		}
+	}
+	return pats
+}
+
+// runStats prints the wc-compatible report for -mode wc: one row per
+// input, a totals row when there are several, nonzero exit in -strict
+// mode when inputs failed.
+func runStats(files []string, start time.Time) {
+	stats, total, err := wordcount.StatFiles(files, buildOptions(nil))
+	inputErrs := wordcount.InputErrors(err)
+	for _, ie := range inputErrs {
+		log.Warnf("Skipped %s: %v", ie.Name, ie.Err)
+	}
+	fmt.Printf("%10s %10s %12s %8s  %s\n", "Lines", "Words", "Bytes", "MaxLine", "File")
+	for _, s := range stats {
+		fmt.Printf("%10d %10d %12d %8d  %s\n", s.Lines, s.Words, s.Bytes, s.MaxLine, s.Name)
+	}
+	if len(stats) > 1 {
+		fmt.Printf("%10d %10d %12d %8d  %s\n", total.Lines, total.Words, total.Bytes, total.MaxLine, total.Name)
+	}
+	fmt.Printf("Processing took: %v\n", time.Since(start))
+	if strict && len(inputErrs) > 0 {
+		if len(stats) == 0 {
+			os.Exit(exitFailure)
+		}
+		os.Exit(exitPartial)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+
+	"word_counter/wordcount"
+)
+
+// diffFlagSet registers the flags the diff subcommand accepts: the
+// counting flags (both sides are tokenized identically) plus what the
+// comparison itself needs.
+func diffFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	commonFlags(fs)
+	countingFlags(fs)
+	fs.BoolVar(&recursive, "r", false, "process directories recursively")
+	fs.BoolVar(&followLinks, "follow-symlinks", false, "resolve symlinks during recursive walks (cycles are detected and visited once)")
+	fs.IntVar(&topN, "top", 0, "report only the N largest changes")
+	fs.BoolVar(&relative, "relative", false, "order by relative change instead of absolute, surfacing rare words that exploded")
+	fs.StringVar(&format, "format", "", "output format: table (default), csv, or tsv")
+	return fs
+}
+
+// runDiff counts two corpora — or loads saved csv/tsv listings — and
+// reports the words whose counts changed the most, for before/after
+// documentation or release-notes analysis.
+func runDiff(args []string) {
+	fs := diffFlagSet()
+	fs.Parse(args)
+	shutdown := setup(fs)
+	defer shutdown()
+
+	if fs.NArg() != 2 {
+		fmt.Fprintf(os.Stderr, "Usage: %s diff [flags] OLD NEW\n\nEach side is a saved word,count listing (-o with -format csv or tsv)\nor an input to count: a file, directory, archive, glob, or URL.\n", os.Args[0])
+		os.Exit(exitNoInputs)
+	}
+	opts := buildOptions(nil)
+	before := loadOrCount(fs.Arg(0), opts)
+	after := loadOrCount(fs.Arg(1), opts)
+
+	deltas := wordcount.DiffCounts(before, after, relative)
+	if topN > 0 && topN < len(deltas) {
+		deltas = deltas[:topN]
+	}
+	if err := wordcount.WriteDeltas(os.Stdout, deltas, format); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// loadOrCount resolves one side of the diff: a file that parses as a
+// saved word,count listing is loaded directly, anything else is counted
+// with the usual pipeline.
+func loadOrCount(arg string, opts wordcount.Options) map[string]int {
+	if counts, err := wordcount.ReadCountsFile(arg); err == nil {
+		log.Infof("Loaded saved counts from %s (%d distinct words)", arg, len(counts))
+		return counts
+	}
+	files := wordcount.ExpandArgs([]string{arg}, recursive, followLinks)
+	if len(files) == 0 {
+		log.Fatalf("Nothing to count for %s", arg)
+	}
+	result, err := wordcount.ProcessFilesContext(context.Background(), files, opts)
+	for _, ie := range wordcount.InputErrors(err) {
+		log.Warnf("Skipped %s: %v", ie.Name, ie.Err)
+	}
+	if err != nil && result == nil {
+		log.Fatal(err)
+	}
+	return result.Counts
+}
@@ -0,0 +1,610 @@
+// Command wordcounter is a thin CLI wrapper around the wordcount package.
+//
+// It is organized as subcommands: count (the default) runs the pipeline
+// over files, serve runs the long-lived HTTP job server or an RPC
+// worker. Invocations that name no subcommand are treated as count, so
+// `wordcounter -top 5 file.txt` keeps working.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	_ "net/http/pprof" // Registers /debug/pprof handlers on the default mux
+	"os"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+
+	"word_counter/wordcount"
+)
+
+var (
+	profileType string        // Flag to specify the type of profiling (cpu/mem/block/trace)
+	profileOut  string        // Directory to save the profile bundle into
+	recursive   bool          // Process directory arguments recursively
+	followLinks bool          // Resolve symlinks during recursive walks
+	dedupe      bool          // Drop files whose content duplicates an earlier one
+	maxFileSize string        // Skip files larger than this size
+	truncLarge  bool          // Truncate oversized files instead of skipping
+	includeGlob string        // Only process files matching these globs
+	excludeGlob string        // Skip files matching these globs
+	topN        int           // Print only the N most frequent words
+	sortBy      string        // Sort key for printed output: count or word
+	ascending   bool          // Sort ascending instead of descending
+	descending  bool          // Sort descending (the default for count)
+	format      string        // Output format: table, csv, or tsv
+	freqCols    bool          // Add percent and cumulative percent columns
+	outputPath  string        // Write results to this file instead of stdout
+	outputSink  string        // Also write results to a sink (e.g. sqlite:counts.db)
+	bufSize     int           // Read buffer size in bytes for each worker
+	stopwords   string        // Stop word list: a file path, or "builtin"
+	minLen      int           // Drop tokens shorter than this many runes
+	maxLen      int           // Drop tokens longer than this many runes
+	caseSens    bool          // Count words case-sensitively
+	locale      string        // BCP 47 tag for locale-aware case folding
+	segmenter   string        // Tokenization rules: letters, uax29, or cjk
+	mode        string        // What to count: words or chars
+	stemLang    string        // Snowball stemming language code
+	lemmaSpec   string        // Lemma table: a language code, or a file path
+	tokenRegex  string        // Custom token pattern overriding the segmenter
+	countRegex  string        // Count raw matches of this pattern instead of words
+	pluginPath  string        // Go plugin (.so) supplying a custom Mapper/Reducer
+	wasmPlugin  string        // WebAssembly tokenizer module (.wasm)
+	extract     string        // Pre-tokenization text extractor (html, pdf, markdown)
+	mdCode      bool          // Count code blocks in markdown extraction
+	csvColumn   string        // Tokenize only these CSV columns
+	jsonField   string        // Tokenize only this JSON field (dot path)
+	encodingIn  string        // Input character encoding override
+	invalidU8   string        // Policy for invalid UTF-8 bytes
+	normalize   string        // Unicode normalization form: nfc or nfkc
+	foldMarks   bool          // Strip diacritics so café and cafe merge
+	withDigits  bool          // Treat digits as word characters
+	keepApos    bool          // Keep internal apostrophes in tokens
+	expandContr bool          // Expand common English contractions
+	hyphens     string        // Hyphenated compound policy: keep, split, or both
+	perFile     bool          // Print a per-input breakdown before the totals
+	tfidf       bool          // Report TF-IDF scores instead of raw counts
+	zipf        bool          // Emit rank-frequency pairs with a fitted Zipf exponent
+	vocab       bool          // Report per-file vocabulary statistics
+	richness    bool          // Report hapax counts and richness measures
+	sentiment   string        // Sentiment lexicon: a file path, or "builtin"
+	ngram       int           // Count n-grams of this many words instead of single words
+	cooccur     string        // Co-occurrence spec, e.g. window=5
+	maxMem      string        // Approximate memory budget for reducer state (e.g. 512M)
+	combFlush   int           // Combiner flush threshold in distinct entries
+	combBytes   string        // Combiner flush threshold in bytes (e.g. 64M)
+	maxReadRate string        // Aggregate read throughput cap (e.g. 50M)
+	readWorkers int           // Dedicated I/O reader goroutines (0 = workers read themselves)
+	stdlibMap   bool          // Count with the built-in Go map instead of the custom table
+	approx      string        // Approximate counting mode: "cms"
+	cmsWidth    int           // Count-Min Sketch counters per row
+	cmsDepth    int           // Count-Min Sketch rows
+	streamSort  bool          // Stream sorted counts via external merge of spill runs
+	useMmap     bool          // Read input files via mmap
+	chunkSize   string        // Split large files into ranges of this size (e.g. 64M)
+	fetchTime   time.Duration // Timeout for each URL input's request
+	fetchLimit  string        // Fail URL inputs whose body exceeds this size
+	maxWorkers  int           // Number of workers for processing files
+	serveWorker string        // Serve as a remote counting worker on this address
+	coordinate  string        // Coordinate these remote workers (comma-separated addresses)
+	serveAddr   string        // Serve counting jobs over HTTP on this address
+	checkpoint  string        // Persist progress to this file and resume from it
+	indexFile   string        // Persistent per-file count index for incremental runs
+	relative    bool          // Order diff output by relative change
+	benchSize   string        // Total synthetic corpus size for bench
+	benchFiles  int           // Number of files in the synthetic corpus
+	benchVocab  int           // Vocabulary size of the synthetic corpus
+	benchSkew   float64       // Zipf exponent of the synthetic corpus
+	benchSeed   int64         // Seed for reproducible corpus generation
+	benchRuns   int           // How many timed pipeline runs bench does
+	progress    bool          // Render a live progress line on stderr
+	timeout     time.Duration // Cancel the run after this long
+	strict      bool          // Exit nonzero when any input cannot be processed
+	metricsAddr string        // Serve Prometheus metrics on this address
+	otelTrace   string        // Write OpenTelemetry spans to this file
+	pprofAddr   string        // Serve live pprof profiles on this address
+	logLevel    string        // Minimum logrus level to emit
+	logFormat   string        // Log output format: text or json
+	watchDir    string        // Watch this directory and keep totals updated
+	kafkaAddrs  string        // Consume a Kafka topic from these brokers (comma-separated)
+	kafkaTopic  string        // Kafka topic to consume
+	kafkaGroup  string        // Kafka consumer group ID for offset commits
+	snapshotInt time.Duration // How often streaming modes report the aggregate
+	configPath  string        // YAML config file supplying flag defaults
+)
+
+// Exit codes reported in -strict mode so CI jobs and batch schedulers can
+// tell failure modes apart. Code 1 stays reserved for fatal errors.
+const (
+	exitNoInputs = 2 // there was nothing to process
+	exitPartial  = 3 // some inputs failed; the results cover the rest
+	exitFailure  = 4 // every input failed
+)
+
+func main() {
+	args := os.Args[1:]
+	if len(args) > 0 {
+		switch args[0] {
+		case "count":
+			runCount(args[1:])
+			return
+		case "serve":
+			runServe(args[1:])
+			return
+		case "diff":
+			runDiff(args[1:])
+			return
+		case "merge":
+			runMerge(args[1:])
+			return
+		case "bench":
+			runBench(args[1:])
+			return
+		case "profile":
+			runProfile(args[1:])
+			return
+		case "completion":
+			runCompletion(args[1:])
+			return
+		case "version":
+			runVersion()
+			return
+		case "help", "-h", "-help", "--help":
+			usage(os.Stdout)
+			return
+		}
+	}
+	// Anything else — flags, file names, or nothing at all — is the
+	// pre-subcommand invocation style and means count
+	runCount(args)
+}
+
+// usage lists the subcommands; per-command flags print via <command> -h.
+func usage(w *os.File) {
+	fmt.Fprintf(w, `Usage: %s <command> [flags] [arguments]
+
+Commands:
+  count       count words in files, directories, archives, or stdin (default)
+  serve       run the HTTP job server or an RPC counting worker
+  diff        compare two corpora or saved listings by count change
+  merge       combine saved count files into one aggregate
+  bench       generate a synthetic corpus and report pipeline throughput
+  profile     compare saved profile bundles (profile diff OLD NEW)
+  completion  print a completion script for bash, zsh, or fish
+  version     print version and build information
+
+Run %s <command> -h for the flags each command accepts.
+`, os.Args[0], os.Args[0])
+}
+
+// commonFlags registers the flags every subcommand accepts: logging,
+// the config file, and the observability listeners.
+func commonFlags(fs *flag.FlagSet) {
+	fs.StringVar(&metricsAddr, "metrics-addr", "", "serve Prometheus metrics on this address under /metrics (e.g. :9100)")
+	fs.StringVar(&otelTrace, "otel-trace", "", "write OpenTelemetry spans (pipeline, per-input map, reduce) to this file as JSON")
+	fs.StringVar(&pprofAddr, "pprof-addr", "", "serve live pprof profiles on this address under /debug/pprof (e.g. :6060)")
+	fs.StringVar(&logLevel, "log-level", "", "minimum log level: debug, info, warn, or error (default info)")
+	fs.StringVar(&logFormat, "log-format", "", "log format: text (default) or json, for log collectors")
+	fs.StringVar(&configPath, "config", "", "YAML config file with flag-name keys; flags and WORDCOUNTER_* env vars override it")
+}
+
+// countingFlags registers the flags that configure how words are
+// tokenized and counted; both local counting and the server accept them.
+func countingFlags(fs *flag.FlagSet) {
+	fs.IntVar(&maxWorkers, "workers", 0, "number of worker goroutines (default: WORDCOUNTER_WORKERS env var, then number of CPUs)")
+	fs.IntVar(&bufSize, "bufsize", 0, "read buffer size in bytes (default 1 MB)")
+	fs.StringVar(&stopwords, "stopwords", "", "stop word file (one word per line), or \"builtin\" for the English list")
+	fs.IntVar(&minLen, "min-len", 0, "drop tokens shorter than this many characters")
+	fs.IntVar(&maxLen, "max-len", 0, "drop tokens longer than this many characters")
+	fs.BoolVar(&caseSens, "case-sensitive", false, "count words case-sensitively instead of lowercasing")
+	fs.StringVar(&locale, "locale", "", "BCP 47 language tag for locale-aware case folding (e.g. tr for Turkish dotless I)")
+	fs.StringVar(&mode, "mode", "", "what to count: words (default), chars for code point frequencies, wc for line/word/byte statistics, or lengths for a token-length histogram")
+	fs.StringVar(&segmenter, "segmenter", "", "word segmentation rules: letters (default), uax29, or cjk")
+	fs.StringVar(&stemLang, "stem", "", "apply Snowball stemming for the given language (e.g. en)")
+	fs.StringVar(&lemmaSpec, "lemma", "", "lemmatize via a dictionary: a language code (en) or a form<TAB>lemma file; applied before -stem")
+	fs.StringVar(&tokenRegex, "token-regex", "", "regular expression defining what counts as a token")
+	fs.StringVar(&countRegex, "count-regex", "", "count raw matches of this pattern (e.g. an IP address regex) instead of words")
+	fs.StringVar(&extract, "extract", "", "extract text before counting: html (strips tags, scripts, styles), pdf (.pdf files are detected automatically), or markdown (strips syntax, URLs, code)")
+	fs.BoolVar(&mdCode, "markdown-code", false, "with -extract markdown, count fenced code blocks and inline code too")
+	fs.StringVar(&csvColumn, "csv-column", "", "parse inputs as CSV and count only these columns (header names or 1-based indexes, comma-separated)")
+	fs.StringVar(&jsonField, "json-field", "", "parse inputs as JSON lines and count only the string field at this dot path (e.g. .message)")
+	fs.StringVar(&encodingIn, "encoding", "", "input encoding, e.g. utf-16le, latin1, shift_jis (default: detect UTF-16/UTF-8, else Windows-1252)")
+	fs.StringVar(&invalidU8, "invalid-utf8", "replace", "what to do with invalid UTF-8 bytes: replace, skip, or error")
+	fs.StringVar(&normalize, "normalize", "", "Unicode-normalize inputs before counting: nfc or nfkc")
+	fs.BoolVar(&foldMarks, "fold-diacritics", false, "strip diacritics before counting so café and cafe merge")
+	fs.BoolVar(&withDigits, "include-digits", false, "treat digits as word characters (keep tokens like 2024 and sha256)")
+	fs.BoolVar(&keepApos, "apostrophes", false, "keep internal apostrophes so don't and o'clock stay single tokens")
+	fs.BoolVar(&expandContr, "expand-contractions", false, "expand common English contractions (don't counts as do + not); implies -apostrophes")
+	fs.StringVar(&hyphens, "hyphens", "split", "hyphenated compounds: split into parts, keep as one token, or count both ways")
+	fs.StringVar(&pluginPath, "plugin", "", "Go plugin (.so) exporting a custom Mapper and/or Reducer")
+	fs.StringVar(&wasmPlugin, "wasm-plugin", "", "WebAssembly tokenizer module (.wasm exporting alloc and tokenize)")
+	fs.IntVar(&ngram, "ngram", 0, "count n-grams of this many consecutive words (e.g. 2 for bigrams)")
+	fs.StringVar(&cooccur, "cooccur", "", "count word pairs within a token window instead of single words, e.g. window=5")
+	fs.StringVar(&maxMem, "max-mem", "", "approximate memory budget for count state, spilling to disk beyond it (e.g. 512M, 2G)")
+	fs.IntVar(&combFlush, "combiner-flush", 0, "flush a worker's partial counts to the reducers every N distinct entries")
+	fs.StringVar(&combBytes, "combiner-flush-bytes", "", "flush a worker's partial counts every time they reach this size (e.g. 64M)")
+	fs.StringVar(&maxReadRate, "max-read-rate", "", "cap aggregate read throughput in bytes/sec across all workers (e.g. 50M)")
+	fs.IntVar(&readWorkers, "read-workers", 0, "dedicated I/O reader goroutines feeding the tokenizer workers; size for the storage device (0 = workers read themselves)")
+	fs.BoolVar(&stdlibMap, "stdlib-map", false, "count with the built-in Go map instead of the open-addressing table, for comparison")
+	fs.StringVar(&approx, "approx", "", "approximate counting in fixed memory: cms (Count-Min Sketch; reports heavy hitters and error bounds) or hll (HyperLogLog; estimates distinct words per file and total)")
+	fs.IntVar(&cmsWidth, "cms-width", 262144, "Count-Min Sketch counters per row; additive error is e/width of the token total")
+	fs.IntVar(&cmsDepth, "cms-depth", 4, "Count-Min Sketch rows; the error bound holds with confidence 1-e^-depth")
+	fs.BoolVar(&useMmap, "mmap", false, "read input files via memory mapping (large files only)")
+	fs.StringVar(&chunkSize, "chunk-size", "", "split files larger than twice this size into parallel word-aligned ranges (e.g. 64M)")
+	fs.DurationVar(&fetchTime, "fetch-timeout", 0, "timeout for each URL input's request (e.g. 30s; 0 = no limit)")
+	fs.StringVar(&fetchLimit, "fetch-limit", "", "fail URL inputs whose response body exceeds this size (e.g. 100M)")
+	fs.StringVar(&maxFileSize, "max-file-size", "", "skip local files larger than this size (e.g. 100M, 2G)")
+	fs.BoolVar(&truncLarge, "truncate-large", false, "read the first -max-file-size bytes of oversized files instead of skipping them")
+}
+
+// outputFlags registers the flags that shape a printed count listing.
+func outputFlags(fs *flag.FlagSet) {
+	fs.IntVar(&topN, "top", 0, "print only the N most frequent words")
+	fs.StringVar(&sortBy, "sort", "", "print results sorted by: count or word")
+	fs.BoolVar(&ascending, "asc", false, "sort printed results in ascending order")
+	fs.BoolVar(&descending, "desc", false, "sort printed results in descending order")
+	fs.StringVar(&format, "format", "", "output format: table, csv, or tsv")
+	fs.BoolVar(&freqCols, "freq", false, "add percent-of-total and cumulative percent columns to the listing")
+	fs.StringVar(&outputPath, "o", "", "write results to this file (atomically) instead of stdout")
+	fs.StringVar(&outputSink, "output", "", "also write results to a sink, e.g. sqlite:counts.db or postgres://host/db")
+}
+
+// setup settles flag values from the config file and environment,
+// configures logging, and starts the observability listeners. It runs
+// after fs.Parse in every subcommand; the returned function flushes
+// traces and must be deferred. Precedence for values: command line >
+// environment > config file > built-in defaults.
+func setup(fs *flag.FlagSet) func() {
+	if configPath == "" {
+		configPath = os.Getenv("WORDCOUNTER_CONFIG")
+	}
+	if err := applyConfig(fs, configPath); err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	// Logs always go to stderr so stdout carries nothing but results
+	// and stays safe to pipe into other programs
+	log.SetOutput(os.Stderr)
+	if logLevel != "" {
+		lvl, err := log.ParseLevel(logLevel)
+		if err != nil {
+			log.Warnf("Invalid -log-level %q. Valid options are: debug, info, warn, error", logLevel)
+		} else {
+			log.SetLevel(lvl)
+		}
+	}
+	switch logFormat {
+	case "", "text":
+	case "json":
+		log.SetFormatter(&log.JSONFormatter{})
+	default:
+		log.Warnf("Invalid -log-format %q. Valid options are: text, json", logFormat)
+	}
+
+	// Tracing installs a global provider, so the spans the wordcount
+	// package emits become visible; without it they are no-ops
+	shutdown := func() {}
+	if otelTrace != "" {
+		var err error
+		shutdown, err = setupTracing(otelTrace)
+		if err != nil {
+			log.Fatalf("Failed to set up tracing: %v", err)
+		}
+	}
+
+	// Unlike the one-shot -profile flag, -pprof-addr serves profiles
+	// while the run is in flight, so a stuck job can be inspected with
+	// go tool pprof without restarting it
+	if pprofAddr != "" {
+		go func() {
+			log.Infof("Serving pprof on %s/debug/pprof", pprofAddr)
+			log.Error(http.ListenAndServe(pprofAddr, nil))
+		}()
+	}
+
+	// The metrics listener runs alongside whatever mode follows; it is
+	// most useful for the long-lived server and worker modes
+	if metricsAddr != "" {
+		go func() {
+			log.Error(wordcount.ServeMetrics(metricsAddr))
+		}()
+	}
+	return shutdown
+}
+
+// buildOptions assembles wordcount.Options from the parsed counting
+// flags, resolving the worker count and fataling on malformed values.
+func buildOptions(stop <-chan struct{}) wordcount.Options {
+	// Resolve worker count: flag, then env var, then CPUs on the system
+	if maxWorkers <= 0 {
+		if env := os.Getenv("WORDCOUNTER_WORKERS"); env != "" {
+			n, err := strconv.Atoi(env)
+			if err != nil || n <= 0 {
+				log.Warnf("Ignoring invalid WORDCOUNTER_WORKERS value %q", env)
+			} else {
+				maxWorkers = n
+			}
+		}
+	}
+	if maxWorkers <= 0 {
+		maxWorkers = runtime.NumCPU()
+	}
+
+	opts := wordcount.Options{Stop: stop, Workers: maxWorkers, BufSize: bufSize, MinLen: minLen, MaxLen: maxLen, CaseSensitive: caseSens, Locale: locale, Segmenter: segmenter, Stem: stemLang, Mode: mode, IncludeDigits: withDigits, KeepApostrophes: keepApos, ExpandContractions: expandContr, Hyphens: hyphens, PerFile: perFile || tfidf || vocab || sentiment != "", NGram: ngram, UseMmap: useMmap, Progress: progress, FetchTimeout: fetchTime, Extract: extract, MarkdownCode: mdCode, CSVColumn: csvColumn, JSONField: jsonField, Encoding: encodingIn, InvalidUTF8: invalidU8, Normalize: normalize, FoldDiacritics: foldMarks}
+	if fetchLimit != "" {
+		limit, err := parseSize(fetchLimit)
+		if err != nil {
+			log.Fatalf("Invalid -fetch-limit: %v", err)
+		}
+		opts.FetchLimit = limit
+	}
+	if mode != "" && mode != wordcount.ModeWords && mode != wordcount.ModeChars && mode != wordcount.ModeWC && mode != wordcount.ModeLengths {
+		log.Fatalf("Invalid -mode %q: want words, chars, wc, or lengths", mode)
+	}
+	if hyphens != "" && hyphens != wordcount.HyphenSplit && hyphens != wordcount.HyphenKeep && hyphens != wordcount.HyphenBoth {
+		log.Fatalf("Invalid -hyphens %q: want keep, split, or both", hyphens)
+	}
+	if pluginPath != "" {
+		m, red, err := wordcount.LoadPlugin(pluginPath)
+		if err != nil {
+			log.Fatalf("Failed to load plugin: %v", err)
+		}
+		opts.Mapper = m
+		opts.Reducer = red
+	}
+	if wasmPlugin != "" {
+		if opts.Mapper != nil {
+			log.Fatal("-plugin and -wasm-plugin both supply a mapper; pick one")
+		}
+		m, err := wordcount.LoadWASMMapper(wasmPlugin)
+		if err != nil {
+			log.Fatalf("Failed to load WASM plugin: %v", err)
+		}
+		opts.Mapper = m
+	}
+	if cooccur != "" {
+		spec, ok := strings.CutPrefix(cooccur, "window=")
+		n, err := strconv.Atoi(spec)
+		if !ok || err != nil || n < 1 {
+			log.Fatalf("Invalid -cooccur %q: want window=N", cooccur)
+		}
+		if ngram > 1 {
+			log.Fatal("-cooccur and -ngram are mutually exclusive")
+		}
+		opts.CoWindow = n
+	}
+	if maxFileSize != "" {
+		size, err := parseSize(maxFileSize)
+		if err != nil {
+			log.Fatalf("Invalid -max-file-size: %v", err)
+		}
+		opts.MaxFileSize = size
+		opts.TruncateLarge = truncLarge
+	}
+	if chunkSize != "" {
+		size, err := parseSize(chunkSize)
+		if err != nil {
+			log.Fatalf("Invalid -chunk-size: %v", err)
+		}
+		opts.ChunkSize = size
+	}
+	if maxMem != "" {
+		budget, err := parseSize(maxMem)
+		if err != nil {
+			log.Fatalf("Invalid -max-mem: %v", err)
+		}
+		opts.MaxMem = budget
+	}
+	if maxReadRate != "" {
+		rate, err := parseSize(maxReadRate)
+		if err != nil {
+			log.Fatalf("Invalid -max-read-rate: %v", err)
+		}
+		opts.MaxReadRate = rate
+	}
+	opts.ReadWorkers = readWorkers
+	opts.StdlibMap = stdlibMap
+	switch approx {
+	case "":
+	case "cms":
+		if cmsWidth <= 0 || cmsDepth <= 0 {
+			log.Fatal("-cms-width and -cms-depth must be positive")
+		}
+		opts.Sketch = wordcount.NewCountMinSketch(cmsWidth, cmsDepth)
+	case "hll":
+		opts.Distinct = wordcount.NewDistinctCounter()
+	default:
+		log.Fatalf("Invalid -approx %q: want cms or hll", approx)
+	}
+	opts.CombinerFlush = combFlush
+	if combBytes != "" {
+		size, err := parseSize(combBytes)
+		if err != nil {
+			log.Fatalf("Invalid -combiner-flush-bytes: %v", err)
+		}
+		opts.CombinerFlushBytes = size
+	}
+	if tokenRegex != "" {
+		re, err := regexp.Compile(tokenRegex)
+		if err != nil {
+			log.Fatalf("Invalid -token-regex: %v", err)
+		}
+		opts.TokenRegex = re
+	}
+	if locale != "" {
+		if _, err := language.Parse(locale); err != nil {
+			log.Fatalf("Invalid -locale %q: %v", locale, err)
+		}
+	}
+	if lemmaSpec != "" {
+		var err error
+		if strings.ContainsAny(lemmaSpec, "./\\") {
+			opts.Lemmas, err = wordcount.LoadLemmas(lemmaSpec)
+		} else {
+			opts.Lemmas, err = wordcount.BuiltinLemmas(lemmaSpec)
+		}
+		if err != nil {
+			log.Fatalf("Invalid -lemma: %v", err)
+		}
+	}
+	if countRegex != "" {
+		if tokenRegex != "" {
+			log.Fatal("-count-regex and -token-regex are mutually exclusive")
+		}
+		re, err := regexp.Compile(countRegex)
+		if err != nil {
+			log.Fatalf("Invalid -count-regex: %v", err)
+		}
+		opts.CountRegex = re
+	}
+	if stopwords != "" {
+		if stopwords == "builtin" {
+			opts.Stopwords = wordcount.BuiltinStopwords()
+		} else {
+			set, err := wordcount.LoadStopwords(stopwords)
+			if err != nil {
+				log.Fatalf("Failed to load stop words from %s: %v", stopwords, err)
+			}
+			opts.Stopwords = set
+		}
+	}
+	return opts
+}
+
+// parseSize parses a byte size with an optional K/M/G suffix (powers of 1024).
+func parseSize(s string) (int64, error) {
+	mult := int64(1)
+	switch {
+	case strings.HasSuffix(s, "K"), strings.HasSuffix(s, "k"):
+		mult, s = 1024, s[:len(s)-1]
+	case strings.HasSuffix(s, "M"), strings.HasSuffix(s, "m"):
+		mult, s = 1024*1024, s[:len(s)-1]
+	case strings.HasSuffix(s, "G"), strings.HasSuffix(s, "g"):
+		mult, s = 1024*1024*1024, s[:len(s)-1]
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return n * mult, nil
+}
+
+// orderedCounts applies the -sort/-asc/-desc/-top flags to a result map.
+func orderedCounts(result map[string]int) []wordcount.WordCount {
+	by := sortBy
+	if by == "" {
+		by = wordcount.SortByCount
+	}
+	if by != wordcount.SortByCount && by != wordcount.SortByWord {
+		log.Warnf("Invalid sort key %q. Valid options are: count, word", sortBy)
+		by = wordcount.SortByCount
+	}
+	// Counts default to descending, words to ascending
+	desc := by == wordcount.SortByCount
+	if ascending {
+		desc = false
+	}
+	if descending {
+		desc = true
+	}
+	if topN > 0 && by == wordcount.SortByCount && desc {
+		// The common top-N query avoids sorting the full vocabulary
+		return wordcount.TopN(result, topN)
+	}
+	counts := wordcount.SortCounts(result, by, desc)
+	if topN > 0 && topN < len(counts) {
+		counts = counts[:topN]
+	}
+	return counts
+}
+
+// applyConfig fills in flags that were not set on the command line from
+// WORDCOUNTER_* environment variables (flag name uppercased, dashes to
+// underscores) and then from the YAML config file, whose keys are flag
+// names. A versioned config therefore replaces a long command line while
+// any flag can still be overridden ad hoc. Config keys that the running
+// subcommand does not accept are rejected.
+func applyConfig(fs *flag.FlagSet, path string) error {
+	fromCommandLine := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { fromCommandLine[f.Name] = true })
+
+	fileValues := make(map[string]string)
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		raw := make(map[string]interface{})
+		if err := yaml.Unmarshal(data, raw); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		for key, value := range raw {
+			if fs.Lookup(key) == nil {
+				return fmt.Errorf("%s: unknown option %q", path, key)
+			}
+			fileValues[key] = fmt.Sprintf("%v", value)
+		}
+	}
+
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || fromCommandLine[f.Name] {
+			return
+		}
+		envName := "WORDCOUNTER_" + strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+		value, ok := os.LookupEnv(envName)
+		if !ok {
+			value, ok = fileValues[f.Name]
+		}
+		if !ok {
+			return
+		}
+		if setErr := f.Value.Set(value); setErr != nil {
+			err = fmt.Errorf("option %s=%q: %w", f.Name, value, setErr)
+		}
+	})
+	return err
+}
+
+// setupTracing installs a global OpenTelemetry provider that batches
+// spans to the named file as JSON. In a deployment that ships traces to
+// Jaeger or Tempo, a collector can tail the file, or the provider here
+// can be swapped for an OTLP exporter.
+func setupTracing(path string) (func(), error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	exp, err := stdouttrace.New(stdouttrace.WithWriter(f))
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exp))
+	otel.SetTracerProvider(tp)
+	return func() {
+		if err := tp.Shutdown(context.Background()); err != nil {
+			log.Warnf("Failed to flush traces: %v", err)
+		}
+		f.Close()
+	}, nil
+}
+
+// stopProfiling stops the profiler if it was started.
+func stopProfiling(profiler interface{ Stop() }) {
+	if profiler != nil {
+		profiler.Stop() // Ensure profiler is stopped when the program exits
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+
+	"word_counter/wordcount"
+)
+
+// mergeFlagSet registers the flags the merge subcommand accepts: only
+// output shaping, since the inputs were already tokenized when saved.
+func mergeFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("merge", flag.ExitOnError)
+	commonFlags(fs)
+	outputFlags(fs)
+	return fs
+}
+
+// runMerge combines saved count files into one aggregate listing. Each
+// argument is a csv/tsv listing written with -o and -format, or a JSON
+// object of counts as the serve API returns. This is the cheap final
+// reduce for corpora sharded manually across machines.
+func runMerge(args []string) {
+	fs := mergeFlagSet()
+	fs.Parse(args)
+	shutdown := setup(fs)
+	defer shutdown()
+
+	if fs.NArg() == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: %s merge [flags] FILE...\n\nEach file is a saved word,count listing (csv/tsv) or a JSON counts object.\n", os.Args[0])
+		os.Exit(exitNoInputs)
+	}
+	total := make(map[string]int)
+	for _, arg := range fs.Args() {
+		counts, err := wordcount.ReadCountsFile(arg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for w, c := range counts {
+			total[w] += c
+		}
+	}
+
+	counts := orderedCounts(total)
+	if freqCols {
+		var tokens int64
+		for _, c := range total {
+			tokens += int64(c)
+		}
+		if outputPath != "" {
+			if err := wordcount.WriteFreqCountsFile(outputPath, counts, format, tokens); err != nil {
+				log.Fatalf("Failed to write results to %s: %v", outputPath, err)
+			}
+		} else if err := wordcount.WriteFreqCounts(os.Stdout, counts, format, tokens); err != nil {
+			log.Fatal(err)
+		}
+	} else if outputPath != "" {
+		if err := wordcount.WriteCountsFile(outputPath, counts, format); err != nil {
+			log.Fatalf("Failed to write results to %s: %v", outputPath, err)
+		}
+	} else if err := wordcount.WriteCounts(os.Stdout, counts, format); err != nil {
+		log.Fatal(err)
+	}
+	if outputSink != "" {
+		if err := wordcount.WriteSink(outputSink, &wordcount.Result{Counts: total}); err != nil {
+			log.Errorf("Failed to write results to %s: %v", outputSink, err)
+		}
+	}
+	fmt.Printf(" Total words: %v\n", len(total))
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+
+	"word_counter/wordcount"
+)
+
+// profileFlagSet registers the flags the profile subcommand accepts.
+func profileFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("profile", flag.ExitOnError)
+	commonFlags(fs)
+	fs.IntVar(&topN, "top", 20, "report only the N largest changes per profile")
+	return fs
+}
+
+// runProfile dispatches the profile subcommand; diff is the only verb.
+func runProfile(args []string) {
+	if len(args) == 0 || args[0] != "diff" {
+		fmt.Fprintf(os.Stderr, "Usage: %s profile diff [flags] OLD NEW\n\nEach side is a profile bundle directory written with -profile and\n-profile-out, or a single .pprof file.\n", os.Args[0])
+		os.Exit(exitNoInputs)
+	}
+	runProfileDiff(args[1:])
+}
+
+// runProfileDiff compares two profile runs function by function,
+// reporting the largest CPU or allocation changes — the numbers an
+// optimization loop watches between a baseline bundle and a candidate.
+func runProfileDiff(args []string) {
+	fs := profileFlagSet()
+	fs.Parse(args)
+	shutdown := setup(fs)
+	defer shutdown()
+
+	if fs.NArg() != 2 {
+		fmt.Fprintf(os.Stderr, "Usage: %s profile diff [flags] OLD NEW\n", os.Args[0])
+		os.Exit(exitNoInputs)
+	}
+	oldArg, newArg := fs.Arg(0), fs.Arg(1)
+	for _, pair := range bundlePairs(oldArg, newArg) {
+		if len(pair.name) > 0 {
+			fmt.Printf("== %s ==\n", pair.name)
+		}
+		deltas, unit, err := wordcount.DiffProfiles(pair.old, pair.new)
+		if err != nil {
+			log.Errorf("Failed to diff %s: %v", pair.name, err)
+			continue
+		}
+		if len(deltas) == 0 {
+			fmt.Println("no per-function changes")
+			continue
+		}
+		if err := wordcount.WriteProfileDeltas(os.Stdout, deltas, unit, topN); err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+// profilePair is one comparable profile present in both runs.
+type profilePair struct {
+	name     string
+	old, new string
+}
+
+// bundlePairs matches up the profiles to compare. Directories are
+// bundles as -profile-out writes them: each well-known profile file
+// present in both sides is compared, and one-sided files are reported.
+// Plain file arguments are compared directly.
+func bundlePairs(oldArg, newArg string) []profilePair {
+	oldInfo, err1 := os.Stat(oldArg)
+	newInfo, err2 := os.Stat(newArg)
+	if err1 != nil || err2 != nil {
+		if err1 != nil {
+			log.Fatal(err1)
+		}
+		log.Fatal(err2)
+	}
+	if !oldInfo.IsDir() && !newInfo.IsDir() {
+		return []profilePair{{old: oldArg, new: newArg}}
+	}
+	if oldInfo.IsDir() != newInfo.IsDir() {
+		log.Fatalf("Cannot compare a bundle directory with a single file (%s vs %s)", oldArg, newArg)
+	}
+	var pairs []profilePair
+	for _, name := range []string{"cpu.pprof", "mem.pprof", "block.pprof"} {
+		oldPath := filepath.Join(oldArg, name)
+		newPath := filepath.Join(newArg, name)
+		_, oldErr := os.Stat(oldPath)
+		_, newErr := os.Stat(newPath)
+		switch {
+		case oldErr == nil && newErr == nil:
+			pairs = append(pairs, profilePair{name: name, old: oldPath, new: newPath})
+		case oldErr == nil:
+			log.Warnf("%s exists only in %s; skipping", name, oldArg)
+		case newErr == nil:
+			log.Warnf("%s exists only in %s; skipping", name, newArg)
+		}
+	}
+	if len(pairs) == 0 {
+		log.Fatalf("No comparable profiles between %s and %s", oldArg, newArg)
+	}
+	return pairs
+}
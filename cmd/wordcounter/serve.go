@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+
+	log "github.com/sirupsen/logrus"
+
+	"word_counter/wordcount"
+)
+
+// serveFlagSet registers the flags the serve subcommand accepts.
+func serveFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	commonFlags(fs)
+	countingFlags(fs)
+	fs.StringVar(&serveAddr, "addr", ":8080", "serve counting jobs over HTTP on this address")
+	fs.StringVar(&serveWorker, "worker", "", "serve as a remote counting worker on this address instead of the HTTP API (e.g. :9090)")
+	return fs
+}
+
+// runServe runs the long-lived modes: the HTTP job server by default, or
+// an RPC counting worker with -worker. Jobs are counted with the same
+// tokenization flags count accepts; listing flags stay with count, since
+// the API returns raw counts as JSON.
+func runServe(args []string) {
+	fs := serveFlagSet()
+	fs.Parse(args)
+	shutdown := setup(fs)
+	defer shutdown()
+
+	// Worker mode serves RPC requests until killed and never counts on
+	// its own initiative; the coordinator ships the options per job
+	if serveWorker != "" {
+		log.Fatal(wordcount.ServeWorker(serveWorker))
+	}
+	log.Fatal(wordcount.NewServer(buildOptions(nil)).ListenAndServe(serveAddr))
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// runVersion prints the module version and the VCS metadata the Go
+// toolchain embeds at build time, so bug reports can name exact builds.
+func runVersion() {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		fmt.Println("wordcounter (build info unavailable)")
+		return
+	}
+	version := info.Main.Version
+	if version == "" || version == "(devel)" {
+		version = "devel"
+	}
+	var revision, vcsTime, modified string
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.time":
+			vcsTime = s.Value
+		case "vcs.modified":
+			modified = s.Value
+		}
+	}
+	fmt.Printf("wordcounter %s\n", version)
+	if revision != "" {
+		if modified == "true" {
+			revision += " (modified)"
+		}
+		fmt.Printf("  commit: %s\n", revision)
+	}
+	if vcsTime != "" {
+		fmt.Printf("  built:  %s\n", vcsTime)
+	}
+	fmt.Printf("  go:     %s\n", info.GoVersion)
+}
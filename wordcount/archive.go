@@ -0,0 +1,231 @@
+package wordcount
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// IsArchive reports whether the path looks like a supported archive
+// (.zip, .tar, .tar.gz, .tgz) whose members should be counted as
+// individual documents.
+func IsArchive(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.HasSuffix(lower, ".zip") ||
+		strings.HasSuffix(lower, ".tar") ||
+		strings.HasSuffix(lower, ".tar.gz") ||
+		strings.HasSuffix(lower, ".tgz")
+}
+
+// ArchiveInputs lists the regular-file members of an archive and returns
+// one Input per member, named "archive!member" so per-file reporting stays
+// meaningful. Each member is opened lazily when a worker dequeues it.
+func ArchiveInputs(path string) ([]Input, error) {
+	lower := strings.ToLower(path)
+	if strings.HasSuffix(lower, ".zip") {
+		return zipInputs(path)
+	}
+	return tarInputs(path)
+}
+
+// zipInputs returns one Input per regular file inside a zip archive.
+func zipInputs(path string) ([]Input, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	var inputs []Input
+	for _, f := range zr.File {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+		name := f.Name
+		inputs = append(inputs, Input{
+			Name: path + "!" + name,
+			Open: func() (io.ReadCloser, error) { return openZipMember(path, name) },
+		})
+	}
+	return inputs, nil
+}
+
+// openZipMember reopens the archive and returns a reader for one member
+// that closes the archive along with the member.
+func openZipMember(path, name string) (io.ReadCloser, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range zr.File {
+		if f.Name == name {
+			rc, err := f.Open()
+			if err != nil {
+				zr.Close()
+				return nil, err
+			}
+			return &memberReadCloser{Reader: rc, closers: []io.Closer{rc, zr}}, nil
+		}
+	}
+	zr.Close()
+	return nil, fmt.Errorf("member %s not found in %s", name, path)
+}
+
+// tarInputs returns one Input per regular file inside a tar archive,
+// transparently handling gzip-compressed tars.
+func tarInputs(path string) ([]Input, error) {
+	names, err := tarMemberNames(path)
+	if err != nil {
+		return nil, err
+	}
+	inputs := make([]Input, 0, len(names))
+	for _, name := range names {
+		name := name
+		inputs = append(inputs, Input{
+			Name: path + "!" + name,
+			Open: func() (io.ReadCloser, error) { return openTarMember(path, name) },
+		})
+	}
+	return inputs, nil
+}
+
+// tarMemberNames lists the regular-file member names of a tar archive.
+func tarMemberNames(path string) ([]string, error) {
+	file, r, err := openTarStream(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var names []string
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			names = append(names, hdr.Name)
+		}
+	}
+	return names, nil
+}
+
+// openTarMember reopens the archive and scans to the named member. Tars
+// are sequential, so this is a linear scan per member; archives are
+// typically read once per member so the simplicity wins.
+func openTarMember(path, name string) (io.ReadCloser, error) {
+	file, r, err := openTarStream(path)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		if hdr.Typeflag == tar.TypeReg && hdr.Name == name {
+			return &memberReadCloser{Reader: tr, closers: []io.Closer{file}}, nil
+		}
+	}
+	file.Close()
+	return nil, fmt.Errorf("member %s not found in %s", name, path)
+}
+
+// openTarStream opens a tar file, wrapping it in gzip decompression when
+// the extension indicates a compressed tar.
+func openTarStream(path string) (*os.File, io.Reader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	lower := strings.ToLower(path)
+	if strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+		return file, gz, nil
+	}
+	return file, file, nil
+}
+
+// memberReadCloser reads an archive member and closes the underlying
+// archive handles along with it.
+type memberReadCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (m *memberReadCloser) Close() error {
+	var firstErr error
+	for _, c := range m.closers {
+		if err := c.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// inputsForPath turns one expanded path into pipeline inputs, splitting
+// archives into one input per member.
+func inputsForPath(path string, opts Options) []Input {
+	if IsURL(path) {
+		return []Input{urlInput(path, opts)}
+	}
+	if IsRemote(path) {
+		inputs, err := RemoteInputs(path)
+		if err != nil {
+			log.Warnf("Failed to list %s: %v", path, err)
+			return nil
+		}
+		return inputs
+	}
+	if opts.MaxFileSize > 0 && path != "-" {
+		if info, err := os.Stat(path); err == nil && info.Size() > opts.MaxFileSize {
+			if !opts.TruncateLarge {
+				log.Warnf("Skipping %s: %d bytes exceeds -max-file-size %d", path, info.Size(), opts.MaxFileSize)
+				return nil
+			}
+			log.Warnf("Truncating %s to the first %d of %d bytes", path, opts.MaxFileSize, info.Size())
+			return []Input{truncatedInput(path, opts.MaxFileSize)}
+		}
+	}
+	if IsArchive(path) {
+		inputs, err := ArchiveInputs(path)
+		if err != nil {
+			log.Warnf("Failed to read archive %s: %v", path, err)
+			return nil
+		}
+		return inputs
+	}
+	if IsPDF(path) && opts.Extract == "" {
+		// PDFs are extracted by extension unless an explicit extractor
+		// was chosen for everything
+		return []Input{pdfInput(path)}
+	}
+	if opts.ChunkSize > 0 && path != "-" {
+		if chunks := chunkInputs(path, opts.ChunkSize); chunks != nil {
+			return chunks
+		}
+	}
+	if opts.UseMmap && path != "-" {
+		return []Input{mmapInput(path)}
+	}
+	return []Input{FileInput(path)}
+}
@@ -0,0 +1,119 @@
+package wordcount
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// azureClient reads an Azure Blob container through the REST API. The
+// account comes from AZURE_STORAGE_ACCOUNT (or a full endpoint from
+// AZURE_STORAGE_ENDPOINT, which also targets the Azurite emulator), and
+// a SAS token from AZURE_STORAGE_SAS_TOKEN authenticates private
+// containers; without one requests go anonymous, which suffices for
+// containers with public read access.
+// It implements objectStore.
+type azureClient struct {
+	container string
+	endpoint  string
+	sas       string // SAS token query string, without the leading "?"
+}
+
+// newAzureClient resolves the endpoint and SAS token from the environment.
+func newAzureClient(container string) (*azureClient, error) {
+	endpoint := os.Getenv("AZURE_STORAGE_ENDPOINT")
+	if endpoint == "" {
+		account := os.Getenv("AZURE_STORAGE_ACCOUNT")
+		if account == "" {
+			return nil, fmt.Errorf("az://%s: set AZURE_STORAGE_ACCOUNT or AZURE_STORAGE_ENDPOINT", container)
+		}
+		endpoint = "https://" + account + ".blob.core.windows.net"
+	}
+	return &azureClient{
+		container: container,
+		endpoint:  strings.TrimSuffix(endpoint, "/"),
+		sas:       strings.TrimPrefix(os.Getenv("AZURE_STORAGE_SAS_TOKEN"), "?"),
+	}, nil
+}
+
+// do issues a GET, appending the SAS token when one is configured.
+func (c *azureClient) do(u string, query url.Values) (*http.Response, error) {
+	qs := query.Encode()
+	if c.sas != "" {
+		if qs != "" {
+			qs += "&"
+		}
+		qs += c.sas
+	}
+	if qs != "" {
+		u += "?" + qs
+	}
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-ms-version", "2020-10-02")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		return nil, fmt.Errorf("az://%s: %s: %s", c.container, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+// list returns the blob names under prefix, following continuation
+// markers through containers larger than one listing page.
+func (c *azureClient) list(prefix string) ([]string, error) {
+	var names []string
+	marker := ""
+	for {
+		query := url.Values{"restype": {"container"}, "comp": {"list"}}
+		if prefix != "" {
+			query.Set("prefix", prefix)
+		}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+		resp, err := c.do(c.endpoint+"/"+c.container, query)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Blobs struct {
+				Blob []struct {
+					Name string `xml:"Name"`
+				} `xml:"Blob"`
+			} `xml:"Blobs"`
+			NextMarker string `xml:"NextMarker"`
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("az://%s: listing %q: %w", c.container, prefix, err)
+		}
+		for _, blob := range page.Blobs.Blob {
+			names = append(names, blob.Name)
+		}
+		if page.NextMarker == "" {
+			return names, nil
+		}
+		marker = page.NextMarker
+	}
+}
+
+// get streams one blob's content.
+func (c *azureClient) get(key string) (io.ReadCloser, error) {
+	resp, err := c.do(c.endpoint+"/"+c.container+"/"+uriEscape(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
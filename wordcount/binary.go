@@ -0,0 +1,41 @@
+package wordcount
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// sniffBinary peeks at the first block of an input and reports whether
+// it looks like binary data rather than text in any supported encoding.
+// The returned reader replays the peeked bytes.
+func sniffBinary(r io.Reader) (io.Reader, bool) {
+	br := bufio.NewReaderSize(r, detectWindow)
+	buf, _ := br.Peek(detectWindow)
+	return br, looksBinary(buf)
+}
+
+// looksBinary uses the same heuristics as git and grep: a NUL byte or a
+// high ratio of control characters means binary. UTF-16 text is full of
+// NULs, so anything the encoding detector would accept is excused first.
+func looksBinary(buf []byte) bool {
+	if len(buf) == 0 {
+		return false
+	}
+	if len(buf) >= 2 && (buf[0] == 0xFF && buf[1] == 0xFE || buf[0] == 0xFE && buf[1] == 0xFF) {
+		return false // UTF-16 BOM
+	}
+	if looksUTF16(buf, 0) || looksUTF16(buf, 1) {
+		return false
+	}
+	if bytes.IndexByte(buf, 0) >= 0 {
+		return true
+	}
+	ctl := 0
+	for _, b := range buf {
+		if b < 0x20 && b != '\t' && b != '\n' && b != '\r' && b != '\f' || b == 0x7F {
+			ctl++
+		}
+	}
+	return ctl*10 > len(buf) // more than 10% control characters
+}
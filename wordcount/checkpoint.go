@@ -0,0 +1,126 @@
+package wordcount
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// checkpoint is the state persisted between batches of a resumable run:
+// the inputs counted so far and their aggregate counts.
+type checkpoint struct {
+	Done   []string       `json:"done"`
+	Counts map[string]int `json:"counts"`
+}
+
+// loadCheckpoint reads a checkpoint file, returning an empty checkpoint
+// when the file does not exist yet.
+func loadCheckpoint(path string) (*checkpoint, error) {
+	ck := &checkpoint{Counts: make(map[string]int)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return ck, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, ck); err != nil {
+		return nil, err
+	}
+	if ck.Counts == nil {
+		ck.Counts = make(map[string]int)
+	}
+	return ck, nil
+}
+
+// save writes the checkpoint atomically (temp file plus rename) so a
+// crash mid-write leaves the previous checkpoint intact.
+func (ck *checkpoint) save(path string) error {
+	return atomicWriteJSON(path, ck)
+}
+
+// atomicWriteJSON encodes v to a temp file and renames it into place,
+// so a crash mid-write leaves the previous file intact.
+func atomicWriteJSON(path string, v interface{}) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op after a successful rename
+
+	enc := json.NewEncoder(tmp)
+	if err := enc.Encode(v); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// ProcessFilesCheckpointed counts files like ProcessFiles but persists
+// progress to ckptPath after each batch, so an interrupted run resumes
+// where it left off instead of restarting from zero. Files recorded as
+// done in an existing checkpoint are skipped; the checkpoint is removed
+// once the whole run completes.
+func ProcessFilesCheckpointed(files []string, opts Options, ckptPath string) (map[string]int, error) {
+	ck, err := loadCheckpoint(ckptPath)
+	if err != nil {
+		return nil, err
+	}
+	done := make(map[string]struct{}, len(ck.Done))
+	for _, name := range ck.Done {
+		done[name] = struct{}{}
+	}
+	var remaining []string
+	for _, f := range files {
+		if _, ok := done[f]; ok {
+			continue
+		}
+		remaining = append(remaining, f)
+	}
+	if skipped := len(files) - len(remaining); skipped > 0 {
+		log.Infof("Resuming from %s: %d of %d files already counted", ckptPath, skipped, len(files))
+	}
+
+	// Batches keep the pipeline saturated between checkpoints: each
+	// batch is processed with full parallelism, then the aggregate and
+	// the done list are persisted before the next batch starts
+	batchSize := opts.withDefaults().Workers
+	var failures []error
+	for start := 0; start < len(remaining); start += batchSize {
+		end := start + batchSize
+		if end > len(remaining) {
+			end = len(remaining)
+		}
+		batch := remaining[start:end]
+		counts, err := ProcessFiles(batch, opts)
+		if counts == nil {
+			return nil, err
+		}
+		if err != nil {
+			// Unreadable files should not abort a multi-hour run;
+			// carry their errors to the final report
+			failures = append(failures, err)
+		}
+		for word, count := range counts {
+			ck.Counts[word] += count
+		}
+		ck.Done = append(ck.Done, batch...)
+		if err := ck.save(ckptPath); err != nil {
+			return nil, err
+		}
+	}
+
+	// A completed run needs no resume point; leaving it behind would
+	// make a later identical invocation skip everything
+	if err := os.Remove(ckptPath); err != nil && !os.IsNotExist(err) {
+		log.Warnf("Failed to remove checkpoint %s: %v", ckptPath, err)
+	}
+	return ck.Counts, errors.Join(failures...)
+}
@@ -0,0 +1,139 @@
+package wordcount
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// chunkInputs splits a large regular file into word-aligned byte ranges so
+// several workers can process one file concurrently instead of a single
+// worker pinning a core while the rest idle. Each chunk's reader skips the
+// partial word at its start (it belongs to the previous chunk) and reads
+// past its end until the final word completes, so no word is lost or
+// counted twice. Ranges are aligned at ASCII whitespace, which is a safe
+// word boundary for every built-in segmenter.
+//
+// Returns nil when chunking does not apply: the file is smaller than two
+// chunks, not a regular file, or compressed.
+func chunkInputs(path string, chunkSize int64) []Input {
+	if strings.HasSuffix(strings.ToLower(path), ".gz") {
+		return nil // Compressed streams cannot be read from an offset
+	}
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() || info.Size() < 2*chunkSize {
+		return nil
+	}
+
+	size := info.Size()
+	var inputs []Input
+	for start := int64(0); start < size; start += chunkSize {
+		start, end := start, start+chunkSize
+		if end > size {
+			end = size
+		}
+		inputs = append(inputs, Input{
+			Name: fmt.Sprintf("%s@%d-%d", path, start, end),
+			Open: func() (io.ReadCloser, error) { return openChunk(path, start, end) },
+		})
+	}
+	return inputs
+}
+
+// openChunk opens one word-aligned byte range of a file.
+func openChunk(path string, start, end int64) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Seek(start, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+	cr := &chunkReader{
+		file: file,
+		br:   bufio.NewReaderSize(file, 64*1024),
+		pos:  start,
+		end:  end,
+	}
+	if start > 0 {
+		// Skip the tail of a word split by the range start; the
+		// previous chunk reads it to completion
+		for {
+			b, err := cr.br.ReadByte()
+			if err != nil {
+				cr.done = true
+				break
+			}
+			cr.pos++
+			if isSpaceByte(b) {
+				break
+			}
+		}
+	}
+	return cr, nil
+}
+
+// chunkReader reads a byte range of a file, running past the range end
+// until the last word completes.
+type chunkReader struct {
+	file *os.File
+	br   *bufio.Reader
+	pos  int64
+	end  int64
+	done bool
+}
+
+func (c *chunkReader) Read(p []byte) (int, error) {
+	if c.done {
+		return 0, io.EOF
+	}
+	if c.pos < c.end {
+		n := len(p)
+		if int64(n) > c.end-c.pos {
+			n = int(c.end - c.pos)
+		}
+		m, err := c.br.Read(p[:n])
+		c.pos += int64(m)
+		if err != nil {
+			c.done = true
+		}
+		return m, err
+	}
+
+	// Past the range end: keep emitting until the current word finishes
+	i := 0
+	for i < len(p) {
+		b, err := c.br.ReadByte()
+		if err != nil {
+			c.done = true
+			if i > 0 {
+				return i, nil
+			}
+			return 0, io.EOF
+		}
+		p[i] = b
+		i++
+		if isSpaceByte(b) {
+			c.done = true
+			return i, nil
+		}
+	}
+	return i, nil
+}
+
+func (c *chunkReader) Close() error {
+	return c.file.Close()
+}
+
+// isSpaceByte reports whether b is ASCII whitespace, the boundary used to
+// align file chunks.
+func isSpaceByte(b byte) bool {
+	switch b {
+	case ' ', '\t', '\n', '\r', '\f', '\v':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+package wordcount
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// CountMinSketch holds approximate word counts in fixed memory: depth
+// rows of width counters, each word hashed to one cell per row, with
+// the minimum across rows as its estimate. Collisions only ever inflate
+// counts, so an estimate is within εN of the truth (ε = e/width, N =
+// total tokens) with probability at least 1-e^-depth — memory is set by
+// width×depth alone, no matter how large the vocabulary grows. Cells
+// are bumped atomically, so one sketch is shared by every worker in a
+// run with no merge step.
+//
+// A sketch cannot enumerate its words, so alongside the cells it tracks
+// a bounded set of heavy-hitter candidates for TopK; a word enters the
+// set when its estimate beats the smallest tracked one.
+type CountMinSketch struct {
+	width  int
+	depth  int
+	cells  []uint32 // depth rows of width cells, flattened
+	tokens atomic.Int64
+
+	mu     sync.Mutex
+	top    map[string]uint32 // candidate heavy hitters
+	minEst atomic.Int64      // smallest tracked estimate once top is full
+}
+
+// cmsTracked is how many heavy-hitter candidates a sketch keeps; TopK
+// can return at most this many words.
+const cmsTracked = 1024
+
+// NewCountMinSketch returns a sketch with the given dimensions. Width
+// sets the additive error (ε = e/width of the token total) and depth
+// the confidence (1 - e^-depth); memory is width×depth 32-bit cells.
+func NewCountMinSketch(width, depth int) *CountMinSketch {
+	return &CountMinSketch{
+		width: width,
+		depth: depth,
+		cells: make([]uint32, width*depth),
+		top:   make(map[string]uint32, cmsTracked),
+	}
+}
+
+// AddBytes counts one occurrence of the word spelled by tok. The token
+// is only materialized as a string if it qualifies as a heavy-hitter
+// candidate, keeping the common path allocation-free.
+func (s *CountMinSketch) AddBytes(tok []byte) {
+	est := s.add(fnv64aBytes(tok))
+	if int64(est) > s.minEst.Load() {
+		s.track(string(tok), est)
+	}
+}
+
+// Add is the string counterpart of AddBytes, for tokens that were
+// already materialized.
+func (s *CountMinSketch) Add(word string) {
+	est := s.add(fnv64a(word))
+	if int64(est) > s.minEst.Load() {
+		s.track(word, est)
+	}
+}
+
+// add bumps the word's cell in every row and returns the new estimate.
+// The row indexes derive from one 64-bit hash split in two, the
+// standard double-hashing construction.
+func (s *CountMinSketch) add(h uint64) uint32 {
+	h1, h2 := uint32(h), uint32(h>>32)|1
+	est := uint32(math.MaxUint32)
+	for i := 0; i < s.depth; i++ {
+		idx := i*s.width + int((h1+uint32(i)*h2)%uint32(s.width))
+		if v := atomic.AddUint32(&s.cells[idx], 1); v < est {
+			est = v
+		}
+	}
+	s.tokens.Add(1)
+	return est
+}
+
+// track admits a word to the candidate set, evicting the smallest
+// estimate once the set is full.
+func (s *CountMinSketch) track(word string, est uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if old, ok := s.top[word]; ok {
+		if est > old {
+			s.top[word] = est
+		}
+		return
+	}
+	if len(s.top) < cmsTracked {
+		s.top[word] = est
+		return
+	}
+	minWord, minVal := "", uint32(math.MaxUint32)
+	for w, v := range s.top {
+		if v < minVal {
+			minWord, minVal = w, v
+		}
+	}
+	if est <= minVal {
+		s.minEst.Store(int64(minVal))
+		return
+	}
+	delete(s.top, minWord)
+	s.top[word] = est
+	minVal = uint32(math.MaxUint32)
+	for _, v := range s.top {
+		if v < minVal {
+			minVal = v
+		}
+	}
+	s.minEst.Store(int64(minVal))
+}
+
+// Estimate returns the sketch's count for word: never below the true
+// count, and above it by at most the ErrorBound with high probability.
+func (s *CountMinSketch) Estimate(word string) int {
+	h := fnv64a(word)
+	h1, h2 := uint32(h), uint32(h>>32)|1
+	est := uint32(math.MaxUint32)
+	for i := 0; i < s.depth; i++ {
+		idx := i*s.width + int((h1+uint32(i)*h2)%uint32(s.width))
+		if v := atomic.LoadUint32(&s.cells[idx]); v < est {
+			est = v
+		}
+	}
+	return int(est)
+}
+
+// TopK returns up to n heavy-hitter candidates, re-estimated against
+// the finished sketch and sorted by descending count.
+func (s *CountMinSketch) TopK(n int) []WordCount {
+	s.mu.Lock()
+	words := make([]string, 0, len(s.top))
+	for w := range s.top {
+		words = append(words, w)
+	}
+	s.mu.Unlock()
+	counts := make([]WordCount, 0, len(words))
+	for _, w := range words {
+		counts = append(counts, WordCount{Word: w, Count: s.Estimate(w)})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Word < counts[j].Word
+	})
+	if n > 0 && n < len(counts) {
+		counts = counts[:n]
+	}
+	return counts
+}
+
+// Tokens returns how many tokens the sketch has absorbed.
+func (s *CountMinSketch) Tokens() int64 { return s.tokens.Load() }
+
+// Width and Depth report the sketch dimensions; MemoryBytes is the size
+// of the cell array they imply.
+func (s *CountMinSketch) Width() int         { return s.width }
+func (s *CountMinSketch) Depth() int         { return s.depth }
+func (s *CountMinSketch) MemoryBytes() int64 { return int64(s.width) * int64(s.depth) * 4 }
+
+// ErrorBound returns the sketch's guarantee: each estimate exceeds the
+// true count by at most eps×Tokens() with probability at least conf.
+func (s *CountMinSketch) ErrorBound() (eps, conf float64) {
+	return math.E / float64(s.width), 1 - math.Exp(-float64(s.depth))
+}
+
+// fnv64a is 64-bit FNV-1a; the sketch splits it into the two 32-bit
+// hashes double hashing needs.
+func fnv64a(s string) uint64 {
+	const (
+		offset64 = 14695981039346656037
+		prime64  = 1099511628211
+	)
+	h := uint64(offset64)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= prime64
+	}
+	return h
+}
+
+func fnv64aBytes(b []byte) uint64 {
+	const (
+		offset64 = 14695981039346656037
+		prime64  = 1099511628211
+	)
+	h := uint64(offset64)
+	for i := 0; i < len(b); i++ {
+		h ^= uint64(b[i])
+		h *= prime64
+	}
+	return h
+}
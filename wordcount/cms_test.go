@@ -0,0 +1,103 @@
+package wordcount
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestCMSEstimateNeverBelowTrueCount(t *testing.T) {
+	s := NewCountMinSketch(1024, 4)
+	want := make(map[string]int)
+	for i := 0; i < 2000; i++ {
+		word := base26(i)
+		reps := i%9 + 1
+		for r := 0; r < reps; r++ {
+			if r%2 == 0 {
+				s.AddBytes([]byte(word))
+			} else {
+				s.Add(word)
+			}
+		}
+		want[word] = reps
+	}
+	// Collisions only ever inflate cells, so an estimate below the true
+	// count means the cell indexing is broken
+	for word, count := range want {
+		if got := s.Estimate(word); got < count {
+			t.Errorf("Estimate(%q) = %d, below true count %d", word, got, count)
+		}
+	}
+	var tokens int64
+	for _, c := range want {
+		tokens += int64(c)
+	}
+	if s.Tokens() != tokens {
+		t.Errorf("Tokens() = %d, want %d", s.Tokens(), tokens)
+	}
+}
+
+func TestCMSTopKFindsHeavyHitters(t *testing.T) {
+	s := NewCountMinSketch(1<<16, 4)
+	// Ten planted heavy hitters, well separated, in a sea of noise words
+	heavy := make([]string, 10)
+	for i := range heavy {
+		heavy[i] = fmt.Sprintf("heavy%c", 'a'+i)
+		for r := 0; r < 1000-i*50; r++ {
+			s.AddBytes([]byte(heavy[i]))
+		}
+	}
+	for i := 0; i < 5000; i++ {
+		word := []byte("noise" + base26(i))
+		for r := 0; r < i%3+1; r++ {
+			s.AddBytes(word)
+		}
+	}
+
+	top := s.TopK(len(heavy))
+	if len(top) != len(heavy) {
+		t.Fatalf("TopK returned %d entries, want %d", len(top), len(heavy))
+	}
+	for i, wc := range top {
+		if wc.Word != heavy[i] {
+			t.Errorf("TopK[%d] = %q (%d), want planted hitter %q", i, wc.Word, wc.Count, heavy[i])
+		}
+		if wantCount := 1000 - i*50; wc.Count < wantCount {
+			t.Errorf("TopK[%d] count %d below true count %d", i, wc.Count, wantCount)
+		}
+	}
+}
+
+func TestCMSConcurrentAdds(t *testing.T) {
+	// Run with -race: one shared sketch, every goroutine hammering the
+	// same hot word plus its own fillers
+	const (
+		workers = 8
+		hotAdds = 5000
+	)
+	s := NewCountMinSketch(1<<16, 4)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			filler := []byte(fmt.Sprintf("filler%d", w))
+			for i := 0; i < hotAdds; i++ {
+				s.AddBytes([]byte("hot"))
+				s.AddBytes(filler)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if want := int64(workers * hotAdds * 2); s.Tokens() != want {
+		t.Errorf("Tokens() = %d, want %d", s.Tokens(), want)
+	}
+	if got := s.Estimate("hot"); got < workers*hotAdds {
+		t.Errorf("Estimate(hot) = %d, below true count %d", got, workers*hotAdds)
+	}
+	top := s.TopK(1)
+	if len(top) != 1 || top[0].Word != "hot" {
+		t.Errorf("TopK(1) = %v, want the hot word", top)
+	}
+}
@@ -0,0 +1,25 @@
+package wordcount
+
+// contractions maps common English contractions to their expansions,
+// used by Options.ExpandContractions after case folding. Each expansion
+// is counted as its component words. Possessives and words like
+// "o'clock" have no sensible expansion and stay single tokens.
+var contractions = map[string]string{
+	"ain't": "is not", "aren't": "are not", "can't": "can not",
+	"couldn't": "could not", "didn't": "did not", "doesn't": "does not",
+	"don't": "do not", "hadn't": "had not", "hasn't": "has not",
+	"haven't": "have not", "he'd": "he would", "he'll": "he will",
+	"he's": "he is", "i'd": "i would", "i'll": "i will", "i'm": "i am",
+	"i've": "i have", "isn't": "is not", "it'll": "it will",
+	"it's": "it is", "let's": "let us", "mustn't": "must not",
+	"she'd": "she would", "she'll": "she will", "she's": "she is",
+	"shouldn't": "should not", "that's": "that is",
+	"there's": "there is", "they'd": "they would",
+	"they'll": "they will", "they're": "they are",
+	"they've": "they have", "wasn't": "was not", "we'd": "we would",
+	"we'll": "we will", "we're": "we are", "we've": "we have",
+	"weren't": "were not", "what's": "what is", "who's": "who is",
+	"won't": "will not", "wouldn't": "would not", "y'all": "you all",
+	"you'd": "you would", "you'll": "you will", "you're": "you are",
+	"you've": "you have",
+}
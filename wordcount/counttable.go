@@ -0,0 +1,128 @@
+package wordcount
+
+// countTable is an open-addressing hash table specialized for the hot
+// counting path. It is keyed by raw token bytes — lookups hash the
+// bytes directly and compare without converting — and the key string is
+// interned only the first time a word is seen, so repeat words cost one
+// FNV pass and a linear probe instead of the stdlib map's hashing and
+// bucket machinery. Slots with a zero count are empty; live counts are
+// always at least one. Options.StdlibMap swaps the built-in map back in
+// for comparison.
+type countTable struct {
+	entries []countEntry
+	used    int
+}
+
+type countEntry struct {
+	hash uint32
+	word string
+	n    int
+}
+
+// countTableMinSize is the initial slot count; sizes stay powers of two
+// so the probe sequence can mask instead of mod.
+const countTableMinSize = 1024
+
+func newCountTable() *countTable {
+	return &countTable{entries: make([]countEntry, countTableMinSize)}
+}
+
+// incBytes bumps the count for the word spelled by tok, interning it on
+// first sight, and reports whether the word is new to the table.
+func (t *countTable) incBytes(tok []byte) bool {
+	if 4*t.used >= 3*len(t.entries) {
+		t.grow()
+	}
+	h := fnv32aBytes(tok)
+	mask := uint32(len(t.entries) - 1)
+	for i := h & mask; ; i = (i + 1) & mask {
+		e := &t.entries[i]
+		if e.n == 0 {
+			e.hash, e.word, e.n = h, string(tok), 1
+			t.used++
+			return true
+		}
+		// The compiler compares string(tok) == e.word without allocating
+		if e.hash == h && string(tok) == e.word {
+			e.n++
+			return false
+		}
+	}
+}
+
+// inc is the string-keyed counterpart of incBytes, for tokens that were
+// already materialized (n-grams, stemmed words, expanded contractions).
+func (t *countTable) inc(word string) bool {
+	if 4*t.used >= 3*len(t.entries) {
+		t.grow()
+	}
+	h := fnv32a(word)
+	mask := uint32(len(t.entries) - 1)
+	for i := h & mask; ; i = (i + 1) & mask {
+		e := &t.entries[i]
+		if e.n == 0 {
+			e.hash, e.word, e.n = h, word, 1
+			t.used++
+			return true
+		}
+		if e.hash == h && e.word == word {
+			e.n++
+			return false
+		}
+	}
+}
+
+// grow doubles the slot count and reinserts every live entry using its
+// cached hash, keeping the load factor under three quarters.
+func (t *countTable) grow() {
+	old := t.entries
+	t.entries = make([]countEntry, 2*len(old))
+	mask := uint32(len(t.entries) - 1)
+	for _, e := range old {
+		if e.n == 0 {
+			continue
+		}
+		for i := e.hash & mask; ; i = (i + 1) & mask {
+			if t.entries[i].n == 0 {
+				t.entries[i] = e
+				break
+			}
+		}
+	}
+}
+
+// toMap materializes the table as an ordinary count map, for the
+// combiner's flush payloads.
+func (t *countTable) toMap() map[string]int {
+	m := make(map[string]int, t.used)
+	for _, e := range t.entries {
+		if e.n > 0 {
+			m[e.word] = e.n
+		}
+	}
+	return m
+}
+
+// drainInto adds every live entry to res.
+func (t *countTable) drainInto(res map[string]int) {
+	for _, e := range t.entries {
+		if e.n > 0 {
+			res[e.word] += e.n
+		}
+	}
+}
+
+// fnv32aBytes is fnv32a over raw token bytes, so the hot path can hash
+// without a string conversion.
+func fnv32aBytes(b []byte) uint32 {
+	const (
+		offset32 = 2166136261
+		prime32  = 16777619
+	)
+	h := uint32(offset32)
+	for i := 0; i < len(b); i++ {
+		h ^= uint32(b[i])
+		h *= prime32
+	}
+	return h
+}
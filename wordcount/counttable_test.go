@@ -0,0 +1,82 @@
+package wordcount
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCountTableGrowth(t *testing.T) {
+	// Far past the initial 1024 slots, so the table grows and rehashes
+	// several times while counts accumulate across the boundaries
+	const distinct = 5000
+	table := newCountTable()
+	want := make(map[string]int, distinct)
+	for i := 0; i < distinct; i++ {
+		word := base26(i)
+		reps := i%7 + 1
+		for r := 0; r < reps; r++ {
+			// Exercise both insert paths against the same entries
+			if r%2 == 0 {
+				table.incBytes([]byte(word))
+			} else {
+				table.inc(word)
+			}
+		}
+		want[word] = reps
+	}
+	if table.used != distinct {
+		t.Errorf("table.used = %d, want %d", table.used, distinct)
+	}
+	if got := table.toMap(); !reflect.DeepEqual(got, want) {
+		t.Errorf("toMap diverges after growth: got %d words, want %d", len(got), len(want))
+	}
+	got := make(map[string]int)
+	table.drainInto(got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("drainInto diverges after growth")
+	}
+}
+
+func TestCountTableHashCollision(t *testing.T) {
+	// Two words with the same full 32-bit hash (found by birthday
+	// search offline), so the equality check after the hash compare is
+	// what keeps them apart
+	a, b := "hgxblgv", "qjhwkez"
+	if fnv32a(a) != fnv32a(b) || a == b {
+		t.Fatalf("collision pair %q/%q no longer collides; hash changed?", a, b)
+	}
+
+	table := newCountTable()
+	for i := 0; i < 3; i++ {
+		table.incBytes([]byte(a))
+	}
+	table.inc(b)
+	got := table.toMap()
+	if got[a] != 3 || got[b] != 1 || len(got) != 2 {
+		t.Errorf("colliding words %q/%q counted as %v, want 3 and 1", a, b, got)
+	}
+}
+
+func TestCountWordsTableMatchesMap(t *testing.T) {
+	// The table and the stdlib map must be observationally identical on
+	// the same stream, whichever split of repeats and new words the
+	// generator produces
+	rng := rand.New(rand.NewSource(42))
+	var b strings.Builder
+	for i := 0; i < 20000; i++ {
+		fmt.Fprintf(&b, "%s ", base26(rng.Intn(2000)))
+	}
+	text := b.String()
+
+	viaTable := make(map[string]int)
+	countWords("table", strings.NewReader(text), viaTable, Options{}.withDefaults(), nil)
+	viaMap := make(map[string]int)
+	countWords("map", strings.NewReader(text), viaMap, Options{StdlibMap: true}.withDefaults(), nil)
+
+	if !reflect.DeepEqual(viaTable, viaMap) {
+		t.Errorf("table counts diverge from map counts: %d vs %d words", len(viaTable), len(viaMap))
+	}
+}
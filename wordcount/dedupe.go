@@ -0,0 +1,72 @@
+package wordcount
+
+import (
+	"crypto/sha256"
+	"io"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DedupeFiles returns files with later copies of identical content
+// removed, so mirrored or vendored directories don't silently double
+// every count. Only files that share a size are hashed; each skipped
+// duplicate is logged with the path it mirrors. Stdin, URLs, and
+// storage URIs pass through, as do files that can't be read — the
+// worker reports those errors in the usual place.
+func DedupeFiles(files []string) []string {
+	sizes := make(map[int64]int)
+	for _, f := range files {
+		if info, err := os.Stat(f); err == nil && plainFile(f) {
+			sizes[info.Size()]++
+		}
+	}
+
+	kept := files[:0]
+	firstSeen := make(map[[sha256.Size]byte]string)
+	for _, f := range files {
+		if !plainFile(f) {
+			kept = append(kept, f)
+			continue
+		}
+		info, err := os.Stat(f)
+		if err != nil || sizes[info.Size()] < 2 {
+			// A unique size can't have a duplicate; skip the hash
+			kept = append(kept, f)
+			continue
+		}
+		sum, err := hashFile(f)
+		if err != nil {
+			kept = append(kept, f)
+			continue
+		}
+		if orig, ok := firstSeen[sum]; ok {
+			log.Warnf("Skipping %s: duplicate of %s", f, orig)
+			continue
+		}
+		firstSeen[sum] = f
+		kept = append(kept, f)
+	}
+	return kept
+}
+
+// plainFile reports whether the argument is a local path rather than
+// stdin or a fetched input.
+func plainFile(f string) bool {
+	return f != "-" && !IsURL(f) && !IsRemote(f)
+}
+
+// hashFile returns the SHA-256 of a file's content.
+func hashFile(f string) (sum [sha256.Size]byte, err error) {
+	file, err := os.Open(f)
+	if err != nil {
+		return sum, err
+	}
+	defer file.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return sum, err
+	}
+	copy(sum[:], h.Sum(nil))
+	return sum, nil
+}
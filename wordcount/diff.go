@@ -0,0 +1,119 @@
+package wordcount
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// WordDelta is one word's count in two corpora, for before/after
+// comparisons.
+type WordDelta struct {
+	Word string
+	Old  int
+	New  int
+}
+
+// Delta is the signed count change.
+func (d WordDelta) Delta() int { return d.New - d.Old }
+
+// Rel is the relative change against the old count; a word absent from
+// the old corpus is +Inf (brand new).
+func (d WordDelta) Rel() float64 {
+	if d.Old == 0 {
+		return math.Inf(1)
+	}
+	return float64(d.New-d.Old) / float64(d.Old)
+}
+
+// DiffCounts returns every word whose count differs between the two
+// corpora, ordered by largest absolute change — or largest relative
+// change when byRel is true, which surfaces rare words that exploded
+// rather than common words that drifted. Ties break alphabetically so
+// output is deterministic.
+func DiffCounts(old, new map[string]int, byRel bool) []WordDelta {
+	var deltas []WordDelta
+	for w, n := range new {
+		if o := old[w]; o != n {
+			deltas = append(deltas, WordDelta{Word: w, Old: o, New: n})
+		}
+	}
+	for w, o := range old {
+		if _, ok := new[w]; !ok {
+			deltas = append(deltas, WordDelta{Word: w, Old: o, New: 0})
+		}
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		a, b := deltas[i], deltas[j]
+		if byRel {
+			ra, rb := math.Abs(a.Rel()), math.Abs(b.Rel())
+			if ra != rb {
+				return ra > rb
+			}
+		}
+		if aa, ab := abs(a.Delta()), abs(b.Delta()); aa != ab {
+			return aa > ab
+		}
+		return a.Word < b.Word
+	})
+	return deltas
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// WriteDeltas writes an ordered list of count changes to w in the given
+// format, mirroring WriteCounts: CSV and TSV get a header row, the
+// table format is for terminals. The relative column prints "new" for
+// words with no old count.
+func WriteDeltas(w io.Writer, deltas []WordDelta, format string) error {
+	switch format {
+	case FormatCSV, FormatTSV:
+		cw := csv.NewWriter(w)
+		if format == FormatTSV {
+			cw.Comma = '\t'
+		}
+		if err := cw.Write([]string{"word", "old", "new", "delta", "relative"}); err != nil {
+			return err
+		}
+		for _, d := range deltas {
+			rec := []string{d.Word, strconv.Itoa(d.Old), strconv.Itoa(d.New), strconv.Itoa(d.Delta()), relString(d)}
+			if err := cw.Write(rec); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	case FormatTable, "":
+		if _, err := fmt.Fprintf(w, "%-10s%-10s%-10s%-10s%s\n", "Delta", "Rel", "Old", "New", "Word"); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%-10s%-10s%-10s%-10s%s\n", "-----", "---", "---", "---", "----"); err != nil {
+			return err
+		}
+		for _, d := range deltas {
+			if _, err := fmt.Fprintf(w, "%-+10d%-10s%-10d%-10d%s\n", d.Delta(), relString(d), d.Old, d.New, d.Word); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// relString formats the relative change as a percentage, or "new" when
+// the word has no old count to compare against.
+func relString(d WordDelta) string {
+	if d.Old == 0 {
+		return "new"
+	}
+	return fmt.Sprintf("%+.1f%%", d.Rel()*100)
+}
@@ -0,0 +1,93 @@
+package wordcount
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// transcodeReader converts an input to UTF-8 before tokenization.
+// Options.Encoding forces a named encoding (any WHATWG label:
+// "utf-16le", "latin1", "windows-1252", "shift_jis", ...); otherwise
+// UTF-16 is detected by BOM or NUL-byte pattern, valid UTF-8 passes
+// through untouched (minus a BOM), and anything else falls back to
+// Windows-1252 — the browsers' fallback, and a superset of Latin-1.
+// Stateful encodings like Shift-JIS can't be told apart reliably and
+// need the override.
+func transcodeReader(r io.Reader, opts Options) (io.Reader, error) {
+	if opts.Encoding != "" {
+		enc, err := htmlindex.Get(strings.ToLower(opts.Encoding))
+		if err != nil {
+			return nil, fmt.Errorf("unknown -encoding %q", opts.Encoding)
+		}
+		if enc == unicode.UTF8 {
+			// The UTF-8 decoder silently substitutes U+FFFD; pass the
+			// bytes through so the InvalidUTF8 policy decides instead
+			return r, nil
+		}
+		return transform.NewReader(r, enc.NewDecoder()), nil
+	}
+	return detectEncoding(r), nil
+}
+
+// detectEncoding sniffs the first block of an input and wraps it in the
+// matching decoder.
+// detectWindow is how many leading bytes the detector sniffs.
+const detectWindow = 4096
+
+func detectEncoding(r io.Reader) io.Reader {
+	br := bufio.NewReaderSize(r, detectWindow)
+	buf, _ := br.Peek(detectWindow)
+	switch {
+	case len(buf) >= 2 && buf[0] == 0xFF && buf[1] == 0xFE,
+		len(buf) >= 2 && buf[0] == 0xFE && buf[1] == 0xFF:
+		// BOMOverride consumes the BOM and picks the right endianness
+		return transform.NewReader(br, unicode.BOMOverride(transform.Nop))
+	case looksUTF16(buf, 0):
+		return transform.NewReader(br, unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM).NewDecoder())
+	case looksUTF16(buf, 1):
+		return transform.NewReader(br, unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewDecoder())
+	case validUTF8Prefix(buf, len(buf) == detectWindow):
+		// Already UTF-8; BOMOverride only strips a leading BOM
+		return transform.NewReader(br, unicode.BOMOverride(transform.Nop))
+	}
+	return transform.NewReader(br, charmap.Windows1252.NewDecoder())
+}
+
+// looksUTF16 reports whether mostly-ASCII UTF-16 text without a BOM is
+// likely: the bytes at parity positions are overwhelmingly NUL (parity
+// 0 means the high byte comes first, i.e. big-endian).
+func looksUTF16(buf []byte, parity int) bool {
+	if len(buf) < 16 {
+		return false
+	}
+	nuls := 0
+	for i := parity; i < len(buf); i += 2 {
+		if buf[i] == 0 {
+			nuls++
+		}
+	}
+	return nuls*10 >= len(buf)*4 // at least 80% of the checked positions
+}
+
+// validUTF8Prefix reports whether buf is valid UTF-8. Only when the
+// sniff window was filled (truncated) may a partial trailing rune be
+// forgiven; a short window saw the whole input, so every byte counts.
+func validUTF8Prefix(buf []byte, truncated bool) bool {
+	if !truncated {
+		return utf8.Valid(buf)
+	}
+	for tail := 0; tail < utf8.UTFMax && tail < len(buf); tail++ {
+		if utf8.Valid(buf[:len(buf)-tail]) {
+			return true
+		}
+	}
+	return len(buf) < utf8.UTFMax
+}
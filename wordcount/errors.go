@@ -0,0 +1,40 @@
+package wordcount
+
+// InputError records the failure of a single input (open, decompress, or
+// read), so callers can tell which files a run skipped and why.
+type InputError struct {
+	Name string
+	Err  error
+}
+
+func (e *InputError) Error() string { return e.Name + ": " + e.Err.Error() }
+
+func (e *InputError) Unwrap() error { return e.Err }
+
+// InputErrors extracts the per-input failures from an error returned by
+// the Process functions, flattening errors.Join trees. It returns nil
+// when err contains none, letting callers distinguish "some files were
+// skipped" from a failure of the run itself.
+func InputErrors(err error) []*InputError {
+	var out []*InputError
+	var walk func(error)
+	walk = func(err error) {
+		if err == nil {
+			return
+		}
+		if ie, ok := err.(*InputError); ok {
+			out = append(out, ie)
+			return
+		}
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, e := range u.Unwrap() {
+				walk(e)
+			}
+		case interface{ Unwrap() error }:
+			walk(u.Unwrap())
+		}
+	}
+	walk(err)
+	return out
+}
@@ -0,0 +1,246 @@
+package wordcount
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ExpandArgs turns command-line arguments into a flat list of file paths.
+// When recursive is true, directory arguments are walked and every regular
+// file under them is included; unreadable entries are logged and skipped.
+// Symlinks are skipped unless follow is true, which resolves them with
+// cycle detection so a link loop in a build tree can't run forever or
+// count a file twice. Non-directory arguments (including "-" for stdin)
+// pass through unchanged.
+func ExpandArgs(args []string, recursive, follow bool) []string {
+	var files []string
+	for _, arg := range args {
+		if arg == "-" {
+			files = append(files, arg)
+			continue
+		}
+		// URLs and storage URIs pass through whole: their ? and *
+		// characters are not glob metacharacters
+		if IsURL(arg) || IsRemote(arg) {
+			files = append(files, arg)
+			continue
+		}
+		if hasGlobMeta(arg) {
+			files = append(files, expandGlob(arg)...)
+			continue
+		}
+		info, err := os.Stat(arg)
+		if err != nil {
+			// Let the worker report the open error for consistency
+			files = append(files, arg)
+			continue
+		}
+		if !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+		if !recursive {
+			log.Warnf("Skipping directory %s (use -r to process recursively)", arg)
+			continue
+		}
+		if follow {
+			walkFollow(arg, make(map[string]bool), &files)
+			continue
+		}
+		err = filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				log.Warnf("Skipping %s: %v", path, err)
+				return nil // Keep walking past unreadable entries
+			}
+			if d.Type()&fs.ModeSymlink != 0 {
+				log.Debugf("Skipping symlink %s (use -follow-symlinks to resolve)", path)
+				return nil
+			}
+			if d.Type().IsRegular() {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			log.Warnf("Error walking directory %s: %v", arg, err)
+		}
+	}
+	return files
+}
+
+// walkFollow walks dir, resolving symlinked files and directories. seen
+// records the physical path of every directory and file already
+// visited, so link cycles terminate and a file reachable both directly
+// and through a link is counted once.
+func walkFollow(dir string, seen map[string]bool, files *[]string) {
+	real, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		log.Warnf("Skipping %s: %v", dir, err)
+		return
+	}
+	if seen[real] {
+		log.Debugf("Skipping %s: directory already visited via symlink", dir)
+		return
+	}
+	seen[real] = true
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		log.Warnf("Skipping %s: %v", dir, err)
+		return
+	}
+	for _, e := range entries {
+		p := filepath.Join(dir, e.Name())
+		rp := filepath.Join(real, e.Name())
+		t := e.Type()
+		if t&fs.ModeSymlink != 0 {
+			info, err := os.Stat(p) // resolves the link
+			if err != nil {
+				log.Warnf("Skipping %s: %v", p, err)
+				continue
+			}
+			t = info.Mode().Type()
+			if rp, err = filepath.EvalSymlinks(p); err != nil {
+				log.Warnf("Skipping %s: %v", p, err)
+				continue
+			}
+		}
+		switch {
+		case t.IsDir():
+			walkFollow(p, seen, files)
+		case t.IsRegular():
+			if !seen[rp] {
+				seen[rp] = true
+				*files = append(*files, p)
+			}
+		}
+	}
+}
+
+// FilterFiles applies include and exclude glob patterns to an expanded
+// file list. A file is kept when it matches at least one include (none
+// means include everything) and no exclude; excludes win. A pattern
+// without a slash matches the base name at any depth ("*.md"), one with
+// slashes matches a path suffix with "**" spanning directories
+// ("vendor/**"). Stdin always passes.
+func FilterFiles(files, include, exclude []string) []string {
+	if len(include) == 0 && len(exclude) == 0 {
+		return files
+	}
+	kept := files[:0]
+	for _, f := range files {
+		if f != "-" && !keepFile(f, include, exclude) {
+			continue
+		}
+		kept = append(kept, f)
+	}
+	return kept
+}
+
+func keepFile(f string, include, exclude []string) bool {
+	for _, pat := range exclude {
+		if matchPattern(pat, f) {
+			return false
+		}
+	}
+	if len(include) == 0 {
+		return true
+	}
+	for _, pat := range include {
+		if matchPattern(pat, f) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPattern matches one glob against a file path with gitignore-like
+// anchoring: slash-less patterns match the base name, others match any
+// suffix of the path so "vendor/**" hits vendored trees at any depth.
+func matchPattern(pattern, file string) bool {
+	pattern = filepath.ToSlash(pattern)
+	file = filepath.ToSlash(file)
+	if !strings.Contains(pattern, "/") {
+		ok, err := path.Match(pattern, path.Base(file))
+		return err == nil && ok
+	}
+	return matchSegments(append([]string{"**"}, strings.Split(pattern, "/")...), strings.Split(file, "/"))
+}
+
+// hasGlobMeta reports whether the argument contains shell glob
+// metacharacters and should be expanded internally. This matters on
+// Windows, where the shell passes patterns through unexpanded.
+func hasGlobMeta(arg string) bool {
+	return strings.ContainsAny(arg, "*?[")
+}
+
+// expandGlob expands a glob pattern into matching file paths. Patterns
+// containing "**" match across directory boundaries by walking from the
+// longest literal prefix; other patterns use filepath.Glob semantics.
+func expandGlob(pattern string) []string {
+	pattern = filepath.ToSlash(pattern)
+	if !strings.Contains(pattern, "**") {
+		matches, err := filepath.Glob(filepath.FromSlash(pattern))
+		if err != nil {
+			log.Warnf("Bad glob pattern %s: %v", pattern, err)
+			return nil
+		}
+		if len(matches) == 0 {
+			log.Warnf("No files match pattern %s", pattern)
+		}
+		return matches
+	}
+
+	// Walk from the directory portion before the first metacharacter
+	base := pattern[:strings.IndexAny(pattern, "*?[")]
+	if i := strings.LastIndex(base, "/"); i >= 0 {
+		base = base[:i]
+	} else {
+		base = "."
+	}
+	var matches []string
+	err := filepath.WalkDir(base, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Keep walking past unreadable entries
+		}
+		if d.Type().IsRegular() && matchSegments(strings.Split(pattern, "/"), strings.Split(filepath.ToSlash(path), "/")) {
+			matches = append(matches, path)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Warnf("Error expanding pattern %s: %v", pattern, err)
+	}
+	if len(matches) == 0 {
+		log.Warnf("No files match pattern %s", pattern)
+	}
+	return matches
+}
+
+// matchSegments matches path segments against pattern segments, where a
+// "**" segment matches zero or more path segments.
+func matchSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchSegments(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	ok, err := path.Match(pattern[0], name[0])
+	if err != nil || !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], name[1:])
+}
@@ -0,0 +1,94 @@
+package wordcount
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"golang.org/x/net/html"
+)
+
+// Extraction modes selecting a pre-tokenization text extractor.
+const (
+	ExtractHTML     = "html"
+	ExtractPDF      = "pdf"
+	ExtractMarkdown = "markdown"
+)
+
+// extractReader wraps a decompressed input in the extractor selected by
+// Options.Extract, so markup is stripped before tokenization sees it.
+func extractReader(r io.Reader, opts Options) (io.Reader, error) {
+	switch opts.Extract {
+	case "":
+		return r, nil
+	case ExtractHTML:
+		return &htmlTextReader{z: html.NewTokenizer(r)}, nil
+	case ExtractPDF:
+		return extractPDFStream(r)
+	case ExtractMarkdown:
+		return &mdTextReader{br: bufio.NewReader(r), keepCode: opts.MarkdownCode}, nil
+	}
+	return nil, fmt.Errorf("unsupported extraction mode %q", opts.Extract)
+}
+
+// htmlTextReader streams the visible text of an HTML document: tag soup,
+// scripts, and styles never reach the tokenizer. Block-level tags become
+// newlines so adjacent cells and paragraphs don't fuse into one token;
+// inline tags emit nothing, keeping words split mid-tag intact.
+type htmlTextReader struct {
+	z       *html.Tokenizer
+	storage []byte
+	pending []byte
+	skip    int // depth of script/style/noscript elements being skipped
+	err     error
+}
+
+// htmlSkipped are elements whose text content is not visible prose.
+var htmlSkipped = map[string]bool{"script": true, "style": true, "noscript": true, "template": true}
+
+// htmlBlocks are elements that separate runs of text; the exact list
+// matters little since extra newlines are whitespace to the tokenizer.
+var htmlBlocks = map[string]bool{
+	"p": true, "div": true, "br": true, "li": true, "ul": true, "ol": true,
+	"td": true, "th": true, "tr": true, "table": true, "h1": true, "h2": true,
+	"h3": true, "h4": true, "h5": true, "h6": true, "section": true,
+	"article": true, "header": true, "footer": true, "blockquote": true, "pre": true,
+}
+
+func (h *htmlTextReader) Read(p []byte) (int, error) {
+	for len(h.pending) == 0 {
+		if h.err != nil {
+			return 0, h.err
+		}
+		tt := h.z.Next()
+		switch tt {
+		case html.ErrorToken:
+			h.err = h.z.Err() // io.EOF at end of document
+		case html.StartTagToken, html.SelfClosingTagToken, html.EndTagToken:
+			name, _ := h.z.TagName()
+			tag := string(name)
+			switch {
+			case htmlSkipped[tag]:
+				switch tt {
+				case html.StartTagToken:
+					h.skip++
+				case html.EndTagToken:
+					if h.skip > 0 {
+						h.skip--
+					}
+				}
+			case htmlBlocks[tag]:
+				h.storage = append(h.storage[:0], '\n')
+				h.pending = h.storage
+			}
+		case html.TextToken:
+			if h.skip == 0 {
+				h.storage = append(h.storage[:0], h.z.Text()...)
+				h.pending = h.storage
+			}
+		}
+	}
+	n := copy(p, h.pending)
+	h.pending = h.pending[n:]
+	return n, nil
+}
@@ -0,0 +1,63 @@
+package wordcount
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHLLEstimateAccuracy(t *testing.T) {
+	// The finalizer mix means sequential values exercise the sketch the
+	// same way hashed words do; 3x the standard error keeps the
+	// deterministic inputs comfortably inside the guarantee
+	for _, n := range []int{100, 1000, 10000, 100000, 1000000} {
+		h := NewHyperLogLog()
+		for i := 0; i < n; i++ {
+			h.AddHash(uint64(i))
+		}
+		est := h.Estimate()
+		tolerance := 3 * h.StdError() * float64(n)
+		if math.Abs(float64(est)-float64(n)) > tolerance {
+			t.Errorf("n=%d: estimate %d outside ±%.0f", n, est, tolerance)
+		}
+	}
+}
+
+func TestHLLAddIsIdempotent(t *testing.T) {
+	h := NewHyperLogLog()
+	for r := 0; r < 50; r++ {
+		for i := 0; i < 1000; i++ {
+			h.AddHash(uint64(i))
+		}
+	}
+	est := h.Estimate()
+	if tolerance := 3 * h.StdError() * 1000; math.Abs(float64(est)-1000) > tolerance {
+		t.Errorf("50 passes over 1000 values estimated %d, want ~1000", est)
+	}
+}
+
+func TestHLLMerge(t *testing.T) {
+	a, b := NewHyperLogLog(), NewHyperLogLog()
+	for i := 0; i < 50000; i++ {
+		a.AddHash(uint64(i))
+	}
+	for i := 50000; i < 130000; i++ {
+		b.AddHash(uint64(i))
+	}
+
+	// Disjoint sketches merge to the union cardinality
+	union := NewHyperLogLog()
+	union.Merge(a)
+	union.Merge(b)
+	est := union.Estimate()
+	if tolerance := 3 * union.StdError() * 130000; math.Abs(float64(est)-130000) > tolerance {
+		t.Errorf("disjoint merge estimated %d, want ~130000 ±%.0f", est, tolerance)
+	}
+
+	// Merging a sketch into itself-alike must not move the estimate:
+	// the register-wise max deduplicates
+	before := a.Estimate()
+	a.Merge(a)
+	if after := a.Estimate(); after != before {
+		t.Errorf("self merge moved the estimate from %d to %d", before, after)
+	}
+}
@@ -0,0 +1,67 @@
+package wordcount
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// jsonFieldReader streams the chosen field of JSON inputs, the shape
+// most log pipelines use for text today. The decoder accepts one value
+// per line (JSONL), pretty-printed concatenated objects, and top-level
+// arrays alike; the field is a dot path (".message", "event.text") and
+// arrays along the path fan out, so a path can select many strings per
+// record. Non-string leaves are ignored — counting serialized numbers
+// and objects is never what anyone wants.
+type jsonFieldReader struct {
+	dec     *json.Decoder
+	path    []string
+	pending []byte
+	err     error
+}
+
+// newJSONFieldReader wraps r so only the field at the dot path reaches
+// the tokenizer.
+func newJSONFieldReader(r io.Reader, path string) *jsonFieldReader {
+	return &jsonFieldReader{
+		dec:  json.NewDecoder(r),
+		path: strings.Split(strings.TrimPrefix(path, "."), "."),
+	}
+}
+
+func (j *jsonFieldReader) Read(p []byte) (int, error) {
+	for len(j.pending) == 0 {
+		if j.err != nil {
+			return 0, j.err
+		}
+		var v interface{}
+		if err := j.dec.Decode(&v); err != nil {
+			j.err = err // io.EOF at end of input
+			continue
+		}
+		j.collect(v, j.path)
+	}
+	n := copy(p, j.pending)
+	j.pending = j.pending[n:]
+	return n, nil
+}
+
+// collect walks one decoded value along the remaining path, appending
+// every string it lands on.
+func (j *jsonFieldReader) collect(v interface{}, path []string) {
+	switch t := v.(type) {
+	case []interface{}:
+		for _, e := range t {
+			j.collect(e, path)
+		}
+	case map[string]interface{}:
+		if len(path) > 0 {
+			j.collect(t[path[0]], path[1:])
+		}
+	case string:
+		if len(path) == 0 {
+			j.pending = append(j.pending, t...)
+			j.pending = append(j.pending, '\n')
+		}
+	}
+}
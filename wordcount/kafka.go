@@ -0,0 +1,94 @@
+package wordcount
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// DefaultSnapshotInterval is how often ConsumeKafka reports the running
+// aggregate when no interval is given.
+const DefaultSnapshotInterval = 10 * time.Second
+
+// ConsumeKafka turns the batch counter into a streaming aggregator: it
+// consumes messages from a Kafka topic, tokenizes each message body with
+// the usual options, and maintains running counts. onSnapshot runs every
+// interval with a copy of the aggregate, and once more before returning,
+// so the last messages are never unreported. ConsumeKafka returns when
+// Options.Stop fires or the consumer fails.
+//
+// With a consumer group ID, offsets are committed after counting so a
+// restart resumes where the previous run stopped and several consumers
+// can split the topic's partitions; without one, partition 0 is read
+// from its first offset.
+func ConsumeKafka(brokers []string, topic, group string, interval time.Duration, opts Options, onSnapshot func(total map[string]int)) error {
+	opts = opts.withDefaults()
+	config := kafka.ReaderConfig{Brokers: brokers, Topic: topic}
+	if group != "" {
+		config.GroupID = group
+	}
+	reader := kafka.NewReader(config)
+	defer reader.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The fetch loop and the snapshot ticker share the aggregate under a
+	// mutex; snapshots hand out copies so callers can read them freely
+	var (
+		mu    sync.Mutex
+		total = make(map[string]int)
+	)
+	snapshot := func() map[string]int {
+		mu.Lock()
+		defer mu.Unlock()
+		counts := make(map[string]int, len(total))
+		for w, c := range total {
+			counts[w] = c
+		}
+		return counts
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		for {
+			msg, err := reader.FetchMessage(ctx)
+			if err != nil {
+				errc <- err
+				return
+			}
+			mu.Lock()
+			countWords(topic, bytes.NewReader(msg.Value), total, opts, nil)
+			mu.Unlock()
+			if group != "" {
+				// Committing after counting trades duplicates on crash
+				// for never losing a counted message
+				if err := reader.CommitMessages(ctx, msg); err != nil {
+					errc <- err
+					return
+				}
+			}
+		}
+	}()
+
+	if interval <= 0 {
+		interval = DefaultSnapshotInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-opts.Stop:
+			onSnapshot(snapshot())
+			return nil
+		case <-ticker.C:
+			onSnapshot(snapshot())
+		case err := <-errc:
+			onSnapshot(snapshot())
+			return err
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package wordcount
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadLemmas reads a lemma table: one "form<TAB>lemma" (or
+// space-separated) entry per line, blank lines and # comments ignored.
+// Published lemmatization lists in this format drop in unmodified.
+func LoadLemmas(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	lemmas := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s: bad lemma line %q: want form and lemma", path, line)
+		}
+		lemmas[strings.ToLower(fields[0])] = strings.ToLower(fields[1])
+	}
+	return lemmas, scanner.Err()
+}
+
+// BuiltinLemmas returns the embedded lemma table for a language code.
+// Only English ("en") ships builtin — the irregular verbs and plurals
+// that stemming can never fix, since regular inflections are better
+// handled by -stem. Other languages need a table via LoadLemmas.
+func BuiltinLemmas(lang string) (map[string]string, error) {
+	if lang != "en" {
+		return nil, fmt.Errorf("no builtin lemma table for %q (only en); pass a form<TAB>lemma file instead", lang)
+	}
+	return map[string]string{
+		// Irregular verbs (past and participle forms)
+		"ate": "eat", "eaten": "eat", "began": "begin", "begun": "begin",
+		"bought": "buy", "broke": "break", "broken": "break",
+		"brought": "bring", "built": "build", "came": "come",
+		"caught": "catch", "chose": "choose", "chosen": "choose",
+		"did": "do", "done": "do", "drank": "drink", "drawn": "draw",
+		"drew": "draw", "drove": "drive", "driven": "drive",
+		"fell": "fall", "fallen": "fall", "felt": "feel",
+		"flew": "fly", "flown": "fly", "forgot": "forget",
+		"forgotten": "forget", "found": "find", "gave": "give",
+		"given": "give", "gone": "go", "got": "get", "gotten": "get",
+		"grew": "grow", "grown": "grow", "had": "have", "heard": "hear",
+		"held": "hold", "kept": "keep", "knew": "know", "known": "know",
+		"left": "leave", "lost": "lose", "made": "make", "meant": "mean",
+		"met": "meet", "paid": "pay", "ran": "run", "rose": "rise",
+		"risen": "rise", "said": "say", "sang": "sing", "sat": "sit",
+		"saw": "see", "seen": "see", "sent": "send", "slept": "sleep",
+		"sold": "sell", "spent": "spend", "spoke": "speak",
+		"spoken": "speak", "stood": "stand", "swam": "swim",
+		"taken": "take", "taught": "teach", "thought": "think",
+		"threw": "throw", "thrown": "throw", "told": "tell",
+		"took": "take", "understood": "understand", "was": "be",
+		"went": "go", "were": "be", "woke": "wake", "won": "win",
+		"wore": "wear", "wrote": "write", "written": "write",
+		// Irregular plurals
+		"children": "child", "feet": "foot", "geese": "goose",
+		"lives": "life", "men": "man", "mice": "mouse", "oxen": "ox",
+		"people": "person", "teeth": "tooth", "wives": "wife",
+		"wolves": "wolf", "women": "woman",
+	}, nil
+}
@@ -0,0 +1,88 @@
+package wordcount
+
+import (
+	"bufio"
+	"regexp"
+	"strings"
+)
+
+// Markdown syntax stripped before tokenization. Link and image text
+// survives while URLs are dropped, so documentation corpora don't fill
+// up with http, png, and anchor fragments.
+var (
+	mdImage    = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)  // ![alt](url) -> alt
+	mdLink     = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)   // [text](url) -> text
+	mdRefLink  = regexp.MustCompile(`\[([^\]]*)\]\[[^\]]*\]`)  // [text][ref] -> text
+	mdLinkDef  = regexp.MustCompile(`^\s{0,3}\[[^\]]+\]:\s`)   // [ref]: url lines
+	mdAutoLink = regexp.MustCompile(`<[a-z]+://[^>]*>`)        // <http://...>
+	mdBareURL  = regexp.MustCompile(`[a-z]+://\S+`)            // http://... in prose
+	mdHeading  = regexp.MustCompile(`^\s{0,3}#{1,6}\s+`)       // # headings
+	mdListItem = regexp.MustCompile(`^\s*([-*+]|\d+[.)])\s+`)  // bullets and numbering
+	mdQuote    = regexp.MustCompile(`^\s{0,3}(>\s?)+`)         // blockquote markers
+	mdRule     = regexp.MustCompile(`^\s{0,3}([-*_]\s*){3,}$`) // horizontal rules
+	mdFence    = regexp.MustCompile("^\\s{0,3}(```|~~~)")      // fenced code delimiters
+	mdCodeSpan = regexp.MustCompile("`[^`]*`")                 // inline code
+	mdEmphasis = strings.NewReplacer("**", "", "__", "", "*", "", "~~", "", "`", "", "|", " ")
+)
+
+// mdTextReader streams Markdown with its syntax stripped: headings,
+// emphasis, list and quote markers, tables, and link URLs disappear
+// while the prose survives. Fenced code blocks and inline code spans are
+// dropped unless keepCode is set, since identifiers in code are usually
+// noise in a vocabulary.
+type mdTextReader struct {
+	br       *bufio.Reader
+	pending  []byte
+	inFence  bool
+	keepCode bool
+	err      error
+}
+
+func (m *mdTextReader) Read(p []byte) (int, error) {
+	for len(m.pending) == 0 {
+		if m.err != nil {
+			return 0, m.err
+		}
+		line, err := m.br.ReadString('\n')
+		if err != nil {
+			m.err = err // deliver the final unterminated line first
+		}
+		if cleaned := m.cleanLine(strings.TrimRight(line, "\n")); cleaned != "" || err == nil {
+			m.pending = append(m.pending[:0], cleaned...)
+			m.pending = append(m.pending, '\n')
+		}
+	}
+	n := copy(p, m.pending)
+	m.pending = m.pending[n:]
+	return n, nil
+}
+
+// cleanLine strips one line of Markdown syntax, returning "" for lines
+// that are pure markup (fence delimiters, link definitions, rules).
+func (m *mdTextReader) cleanLine(line string) string {
+	if mdFence.MatchString(line) {
+		m.inFence = !m.inFence
+		return ""
+	}
+	if m.inFence {
+		if m.keepCode {
+			return line
+		}
+		return ""
+	}
+	if mdLinkDef.MatchString(line) || mdRule.MatchString(line) {
+		return ""
+	}
+	line = mdHeading.ReplaceAllString(line, "")
+	line = mdQuote.ReplaceAllString(line, "")
+	line = mdListItem.ReplaceAllString(line, "")
+	if !m.keepCode {
+		line = mdCodeSpan.ReplaceAllString(line, " ")
+	}
+	line = mdImage.ReplaceAllString(line, "$1")
+	line = mdLink.ReplaceAllString(line, "$1")
+	line = mdRefLink.ReplaceAllString(line, "$1")
+	line = mdAutoLink.ReplaceAllString(line, " ")
+	line = mdBareURL.ReplaceAllString(line, " ")
+	return mdEmphasis.Replace(line)
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package wordcount
+
+// mmapInput falls back to ordinary buffered reads on platforms without
+// Unix mmap support.
+func mmapInput(name string) Input {
+	return FileInput(name)
+}
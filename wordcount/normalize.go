@@ -0,0 +1,38 @@
+package wordcount
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// normalizeReader applies Unicode normalization to an input stream so
+// canonically-equivalent spellings — precomposed é versus e plus a
+// combining accent — count as the same word. NFC composes canonically;
+// NFKC additionally folds compatibility forms (ligatures, fullwidth
+// letters) into their plain equivalents. With FoldDiacritics set the
+// stream is additionally decomposed and stripped of combining marks, so
+// "café" and "cafe" merge.
+func normalizeReader(r io.Reader, opts Options) (io.Reader, error) {
+	var compose norm.Form
+	switch strings.ToLower(opts.Normalize) {
+	case "", "nfc":
+		compose = norm.NFC
+	case "nfkc":
+		compose = norm.NFKC
+	default:
+		return nil, fmt.Errorf("unknown -normalize %q: want nfc or nfkc", opts.Normalize)
+	}
+	if !opts.FoldDiacritics {
+		return transform.NewReader(r, compose), nil
+	}
+	// Decompose so accents become separate combining marks, drop the
+	// marks, then recompose whatever is left
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), compose)
+	return transform.NewReader(r, t), nil
+}
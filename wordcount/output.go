@@ -0,0 +1,312 @@
+package wordcount
+
+import (
+	"bufio"
+	"bytes"
+	"container/heap"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WordCount pairs a word with its occurrence count, for ordered output.
+type WordCount struct {
+	Word  string
+	Count int
+}
+
+// Sort keys for SortCounts.
+const (
+	SortByCount = "count"
+	SortByWord  = "word"
+)
+
+// SortCounts returns the result map as a slice ordered by the given key
+// ("count" or "word"), descending when desc is true. Count ties are broken
+// alphabetically so output is deterministic regardless of map iteration order.
+func SortCounts(result map[string]int, by string, desc bool) []WordCount {
+	counts := make([]WordCount, 0, len(result))
+	for w, c := range result {
+		counts = append(counts, WordCount{Word: w, Count: c})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		var less bool
+		switch by {
+		case SortByWord:
+			less = counts[i].Word < counts[j].Word
+		default: // SortByCount
+			if counts[i].Count != counts[j].Count {
+				less = counts[i].Count < counts[j].Count
+			} else {
+				less = counts[i].Word < counts[j].Word
+				return less != desc // Ties stay alphabetical either way
+			}
+		}
+		if desc {
+			return !less
+		}
+		return less
+	})
+	return counts
+}
+
+// TopN returns the n most frequent words in descending count order. Ties
+// are broken alphabetically so output is deterministic. If n is zero or
+// negative, or exceeds the vocabulary size, all words are returned.
+//
+// Rather than sorting the entire vocabulary (O(V log V)), a bounded
+// min-heap of size n is maintained while scanning the map (O(V log n)),
+// so top-10 queries stay cheap even over vocabularies of tens of
+// millions of words.
+func TopN(result map[string]int, n int) []WordCount {
+	if n <= 0 || n >= len(result) {
+		return SortCounts(result, SortByCount, true)
+	}
+
+	h := make(topHeap, 0, n+1)
+	heap.Init(&h)
+	for w, c := range result {
+		if len(h) < n {
+			heap.Push(&h, WordCount{Word: w, Count: c})
+			continue
+		}
+		// Replace the heap minimum when this word outranks it
+		if least(h[0], WordCount{Word: w, Count: c}) {
+			h[0] = WordCount{Word: w, Count: c}
+			heap.Fix(&h, 0)
+		}
+	}
+
+	// Pop into descending rank order
+	counts := make([]WordCount, len(h))
+	for i := len(h) - 1; i >= 0; i-- {
+		counts[i] = heap.Pop(&h).(WordCount)
+	}
+	return counts
+}
+
+// least reports whether a ranks strictly below b in top-N order
+// (lower count, or alphabetically later on ties).
+func least(a, b WordCount) bool {
+	if a.Count != b.Count {
+		return a.Count < b.Count
+	}
+	return a.Word > b.Word
+}
+
+// topHeap is a min-heap of word counts ordered by top-N rank, so the
+// lowest-ranked retained word is always at the root.
+type topHeap []WordCount
+
+func (h topHeap) Len() int            { return len(h) }
+func (h topHeap) Less(i, j int) bool  { return least(h[i], h[j]) }
+func (h topHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *topHeap) Push(x interface{}) { *h = append(*h, x.(WordCount)) }
+func (h *topHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// Output formats for WriteCounts.
+const (
+	FormatTable = "table"
+	FormatCSV   = "csv"
+	FormatTSV   = "tsv"
+)
+
+// WriteCounts writes an ordered list of word counts to w in the given
+// format. CSV and TSV output includes a word,count header row so the
+// files load cleanly into spreadsheets and dataframe libraries.
+func WriteCounts(w io.Writer, counts []WordCount, format string) error {
+	switch format {
+	case FormatCSV, FormatTSV:
+		cw := csv.NewWriter(w)
+		if format == FormatTSV {
+			cw.Comma = '\t'
+		}
+		if err := cw.Write([]string{"word", "count"}); err != nil {
+			return err
+		}
+		for _, wc := range counts {
+			if err := cw.Write([]string{wc.Word, strconv.Itoa(wc.Count)}); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	case FormatTable, "":
+		if _, err := fmt.Fprintf(w, "%-10s%s\n%-10s%s\n", "Count", "Word", "-----", "----"); err != nil {
+			return err
+		}
+		for _, wc := range counts {
+			if _, err := fmt.Fprintf(w, "%-10v%s\n", wc.Count, wc.Word); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// WriteFreqCounts writes an ordered count listing with relative
+// frequency columns: each word's percentage of total tokens and the
+// cumulative percentage down the listing (meaningful mainly for
+// count-sorted output). The total is taken against the full aggregate,
+// so a -top listing still shows each word's share of the whole corpus;
+// pass zero to derive it from the listing itself.
+func WriteFreqCounts(w io.Writer, counts []WordCount, format string, total int64) error {
+	if total <= 0 {
+		for _, wc := range counts {
+			total += int64(wc.Count)
+		}
+	}
+	pct := func(n int64) float64 {
+		if total == 0 {
+			return 0
+		}
+		return 100 * float64(n) / float64(total)
+	}
+	var cum int64
+	switch format {
+	case FormatCSV, FormatTSV:
+		cw := csv.NewWriter(w)
+		if format == FormatTSV {
+			cw.Comma = '\t'
+		}
+		if err := cw.Write([]string{"word", "count", "percent", "cumulative"}); err != nil {
+			return err
+		}
+		for _, wc := range counts {
+			cum += int64(wc.Count)
+			rec := []string{wc.Word, strconv.Itoa(wc.Count),
+				strconv.FormatFloat(pct(int64(wc.Count)), 'f', 4, 64),
+				strconv.FormatFloat(pct(cum), 'f', 4, 64)}
+			if err := cw.Write(rec); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	case FormatTable, "":
+		if _, err := fmt.Fprintf(w, "%-10s%-9s%-9s%s\n%-10s%-9s%-9s%s\n", "Count", "Pct", "Cum", "Word", "-----", "---", "---", "----"); err != nil {
+			return err
+		}
+		for _, wc := range counts {
+			cum += int64(wc.Count)
+			if _, err := fmt.Fprintf(w, "%-10v%-9s%-9s%s\n", wc.Count,
+				fmt.Sprintf("%.2f%%", pct(int64(wc.Count))),
+				fmt.Sprintf("%.2f%%", pct(cum)), wc.Word); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// WriteCountsFile writes counts to the named file atomically: output goes
+// to a temporary file in the same directory which is renamed over the
+// target only after a successful write, so interrupted runs never leave a
+// half-written result file behind.
+func WriteCountsFile(path string, counts []WordCount, format string) error {
+	return writeFileAtomic(path, func(w io.Writer) error {
+		return WriteCounts(w, counts, format)
+	})
+}
+
+// WriteFreqCountsFile is WriteCountsFile with the frequency columns of
+// WriteFreqCounts.
+func WriteFreqCountsFile(path string, counts []WordCount, format string, total int64) error {
+	return writeFileAtomic(path, func(w io.Writer) error {
+		return WriteFreqCounts(w, counts, format, total)
+	})
+}
+
+// writeFileAtomic runs write against a temporary file in the target's
+// directory and renames it over the target only on success.
+func writeFileAtomic(path string, write func(io.Writer) error) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op after a successful rename
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// PrintCounts prints an ordered list of word counts in the same tabular
+// format as PrintResult.
+func PrintCounts(counts []WordCount) {
+	fmt.Printf("%-10s%s\n", "Count", "Word")
+	fmt.Printf("%-10s%s\n", "-----", "----")
+
+	for _, wc := range counts {
+		fmt.Printf("%-10v%s\n", wc.Count, wc.Word)
+	}
+}
+
+// ReadCountsFile loads a saved count listing: csv or tsv written with
+// -o and -format (the delimiter is sniffed from the header line), or a
+// JSON object of word to count as the serve API returns. A file that
+// starts with neither a word,count header nor a JSON object is
+// rejected, which is how callers tell a saved result from a text
+// corpus.
+func ReadCountsFile(path string) (map[string]int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	head, _ := br.Peek(512)
+	if len(bytes.TrimSpace(head)) > 0 && bytes.TrimSpace(head)[0] == '{' {
+		counts := make(map[string]int)
+		if err := json.NewDecoder(br).Decode(&counts); err != nil {
+			return nil, fmt.Errorf("%s: bad JSON counts: %v", path, err)
+		}
+		return counts, nil
+	}
+	cr := csv.NewReader(br)
+	if i := bytes.IndexByte(head, '\n'); i < 0 || bytes.IndexByte(head[:i], '\t') >= 0 {
+		cr.Comma = '\t'
+	}
+	header, err := cr.Read()
+	if err != nil || len(header) < 2 || !strings.EqualFold(header[0], "word") || !strings.EqualFold(header[1], "count") {
+		return nil, fmt.Errorf("%s is not a saved word,count listing", path)
+	}
+	counts := make(map[string]int)
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return counts, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		n, err := strconv.Atoi(rec[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s: bad count for %q: %v", path, rec[0], err)
+		}
+		counts[rec[0]] += n
+	}
+}
@@ -0,0 +1,66 @@
+package wordcount
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// IsPDF reports whether the path names a PDF file whose extracted text
+// should be counted instead of its raw bytes.
+func IsPDF(path string) bool {
+	return strings.HasSuffix(strings.ToLower(path), ".pdf")
+}
+
+// pdfInput returns an Input that extracts a PDF file's text when a
+// worker dequeues it. PDFs are recognized by extension and converted
+// automatically: the cross-reference table lives at the end of the
+// file, so unlike the other extractors this one needs random access.
+func pdfInput(path string) Input {
+	return Input{
+		Name: path,
+		Open: func() (io.ReadCloser, error) { return openPDF(path) },
+	}
+}
+
+// openPDF opens the file and returns a reader over its extracted text.
+func openPDF(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	text, err := pdfText(f, st.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &memberReadCloser{Reader: text, closers: []io.Closer{f}}, nil
+}
+
+// pdfText extracts the plain text of every page.
+func pdfText(r io.ReaderAt, size int64) (io.Reader, error) {
+	doc, err := pdf.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	return doc.GetPlainText()
+}
+
+// extractPDFStream adapts the random-access extractor to the streaming
+// extraction hook: the whole document is buffered first, which -extract
+// pdf accepts in exchange for working on stdin and URL inputs.
+func extractPDFStream(r io.Reader) (io.Reader, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return pdfText(bytes.NewReader(data), int64(len(data)))
+}
@@ -0,0 +1,69 @@
+package wordcount
+
+import (
+	"fmt"
+	"io"
+	"plugin"
+)
+
+// Mapper is the tokenize step a plugin can replace. Map reads one
+// prepared input stream — decompression, transcoding, and extraction
+// have already happened — and emits word counts; the pipeline handles
+// the shuffle and reduce as usual. Implementations must not retain r or
+// emit after returning.
+type Mapper interface {
+	Map(name string, r io.Reader, emit func(word string, count int)) error
+}
+
+// Reducer is the aggregation step a plugin can replace. Reduce folds
+// one partial count map into the running aggregate for a partition;
+// partitions never share keys, so no locking is needed. A custom
+// reducer opts out of the -max-mem spill accounting, which only
+// understands plain summation.
+type Reducer interface {
+	Reduce(into map[string]int, partial map[string]int)
+}
+
+// LoadPlugin opens a Go plugin (built with -buildmode=plugin against
+// this module) and returns whichever of its exported Mapper and Reducer
+// variables it finds:
+//
+//	var Mapper wordcount.Mapper = myTokenizer{}
+//	var Reducer wordcount.Reducer = myAggregator{}
+//
+// Either may be omitted; a plugin exporting neither is an error. Go
+// plugins only load on platforms the plugin package supports and must
+// be built with the same toolchain and dependency versions as the
+// binary loading them.
+func LoadPlugin(path string) (Mapper, Reducer, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var m Mapper
+	if sym, err := p.Lookup("Mapper"); err == nil {
+		switch v := sym.(type) {
+		case Mapper:
+			m = v
+		case *Mapper:
+			m = *v
+		default:
+			return nil, nil, fmt.Errorf("%s: Mapper is %T, want a wordcount.Mapper", path, sym)
+		}
+	}
+	var r Reducer
+	if sym, err := p.Lookup("Reducer"); err == nil {
+		switch v := sym.(type) {
+		case Reducer:
+			r = v
+		case *Reducer:
+			r = *v
+		default:
+			return nil, nil, fmt.Errorf("%s: Reducer is %T, want a wordcount.Reducer", path, sym)
+		}
+	}
+	if m == nil && r == nil {
+		return nil, nil, fmt.Errorf("%s exports neither Mapper nor Reducer", path)
+	}
+	return m, r, nil
+}
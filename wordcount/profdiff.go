@@ -0,0 +1,137 @@
+package wordcount
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	pprofile "github.com/google/pprof/profile"
+)
+
+// ProfileDelta is one function's flat value in two profile runs.
+type ProfileDelta struct {
+	Name string
+	Old  int64
+	New  int64
+}
+
+// Delta is the change from the old run to the new one.
+func (d ProfileDelta) Delta() int64 { return d.New - d.Old }
+
+// DiffProfiles parses two pprof profiles (as -profile writes them) and
+// returns per-function flat deltas ordered by absolute change, plus the
+// sample unit ("nanoseconds", "bytes", or "count"). CPU time and
+// allocated space are preferred when a profile records several sample
+// types, since those are what optimization work tracks.
+func DiffProfiles(oldPath, newPath string) ([]ProfileDelta, string, error) {
+	oldFlats, unit, err := profileFlats(oldPath)
+	if err != nil {
+		return nil, "", err
+	}
+	newFlats, newUnit, err := profileFlats(newPath)
+	if err != nil {
+		return nil, "", err
+	}
+	if unit != newUnit {
+		return nil, "", fmt.Errorf("profiles measure different units (%s vs %s)", unit, newUnit)
+	}
+	names := make(map[string]bool, len(oldFlats)+len(newFlats))
+	for name := range oldFlats {
+		names[name] = true
+	}
+	for name := range newFlats {
+		names[name] = true
+	}
+	deltas := make([]ProfileDelta, 0, len(names))
+	for name := range names {
+		d := ProfileDelta{Name: name, Old: oldFlats[name], New: newFlats[name]}
+		if d.Delta() != 0 {
+			deltas = append(deltas, d)
+		}
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		di, dj := deltas[i].Delta(), deltas[j].Delta()
+		if di < 0 {
+			di = -di
+		}
+		if dj < 0 {
+			dj = -dj
+		}
+		if di != dj {
+			return di > dj
+		}
+		return deltas[i].Name < deltas[j].Name
+	})
+	return deltas, unit, nil
+}
+
+// profileFlats loads one profile and sums each sample's value into its
+// leaf function — the flat attribution pprof's own top view uses.
+func profileFlats(path string) (map[string]int64, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+	defer f.Close()
+	p, err := pprofile.Parse(f)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %v", path, err)
+	}
+	idx := len(p.SampleType) - 1
+	for i, st := range p.SampleType {
+		if st.Type == "cpu" || st.Type == "alloc_space" {
+			idx = i
+			break
+		}
+	}
+	flats := make(map[string]int64)
+	for _, s := range p.Sample {
+		if len(s.Location) == 0 || idx >= len(s.Value) {
+			continue
+		}
+		name := "<unknown>"
+		if lines := s.Location[0].Line; len(lines) > 0 && lines[0].Function != nil {
+			name = lines[0].Function.Name
+		}
+		flats[name] += s.Value[idx]
+	}
+	return flats, p.SampleType[idx].Unit, nil
+}
+
+// WriteProfileDeltas renders the largest changes between two profile
+// runs, positive deltas meaning the new run spends (or allocates) more.
+func WriteProfileDeltas(w io.Writer, deltas []ProfileDelta, unit string, top int) error {
+	if top > 0 && top < len(deltas) {
+		deltas = deltas[:top]
+	}
+	if _, err := fmt.Fprintf(w, "%12s %12s %12s  %s\n", "Delta", "Old", "New", "Function"); err != nil {
+		return err
+	}
+	for _, d := range deltas {
+		if _, err := fmt.Fprintf(w, "%12s %12s %12s  %s\n",
+			formatSample(d.Delta(), unit, true), formatSample(d.Old, unit, false),
+			formatSample(d.New, unit, false), d.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatSample renders a pprof sample value in its unit, signed for
+// deltas.
+func formatSample(v int64, unit string, signed bool) string {
+	sign := ""
+	if signed && v > 0 {
+		sign = "+"
+	}
+	switch unit {
+	case "nanoseconds":
+		return sign + time.Duration(v).Round(10*time.Microsecond).String()
+	case "bytes":
+		return fmt.Sprintf("%s%.2fMB", sign, float64(v)/(1<<20))
+	default:
+		return fmt.Sprintf("%s%d", sign, v)
+	}
+}
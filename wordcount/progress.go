@@ -0,0 +1,104 @@
+package wordcount
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// progressInterval is how often the live progress line is redrawn.
+const progressInterval = 500 * time.Millisecond
+
+// progressTracker renders a live progress line on stderr — files done out
+// of total, bytes read, throughput, and an ETA — so long runs visibly
+// make progress. Workers feed it through atomic counters, keeping the
+// hot path free of locks.
+type progressTracker struct {
+	total int
+	done  atomic.Int64
+	bytes atomic.Int64
+	start time.Time
+	stop  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// startProgress begins rendering progress over total inputs.
+func startProgress(total int) *progressTracker {
+	t := &progressTracker{total: total, start: time.Now(), stop: make(chan struct{})}
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				fmt.Fprintf(os.Stderr, "\r%s", t.line())
+			case <-t.stop:
+				fmt.Fprintf(os.Stderr, "\r%s\n", t.line())
+				return
+			}
+		}
+	}()
+	return t
+}
+
+// reader wraps rc so every byte read is added to the bytes counter.
+func (t *progressTracker) reader(r io.Reader) io.Reader {
+	return &countingReader{r: r, n: &t.bytes}
+}
+
+// fileDone records one fully processed input.
+func (t *progressTracker) fileDone() {
+	t.done.Add(1)
+}
+
+// finish draws the final progress line and stops the renderer.
+func (t *progressTracker) finish() {
+	close(t.stop)
+	t.wg.Wait()
+}
+
+// line formats the current progress state.
+func (t *progressTracker) line() string {
+	done := t.done.Load()
+	bytes := t.bytes.Load()
+	elapsed := time.Since(t.start)
+	rate := float64(bytes) / elapsed.Seconds()
+
+	eta := "--"
+	if done > 0 && int(done) < t.total {
+		remaining := time.Duration(float64(elapsed) / float64(done) * float64(int64(t.total)-done))
+		eta = remaining.Round(time.Second).String()
+	}
+	return fmt.Sprintf("%d/%d files  %s read  %s/s  ETA %s   ",
+		done, t.total, formatBytes(bytes), formatBytes(int64(rate)), eta)
+}
+
+// formatBytes renders a byte count with a binary-unit suffix.
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
+
+// countingReader counts bytes as they pass through to an atomic total.
+type countingReader struct {
+	r io.Reader
+	n *atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
@@ -0,0 +1,64 @@
+package wordcount
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket shared by every worker in a pipeline
+// run, so Options.MaxReadRate bounds the aggregate read throughput
+// rather than each worker's. The bucket holds at most one second of
+// budget, keeping bursts after idle periods bounded.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // bytes per second
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(bytesPerSec int64) *rateLimiter {
+	return &rateLimiter{rate: float64(bytesPerSec), tokens: float64(bytesPerSec), last: time.Now()}
+}
+
+// wait charges n bytes against the bucket, sleeping off any debt so
+// sustained throughput converges on the configured rate.
+func (l *rateLimiter) wait(n int) {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.rate {
+		l.tokens = l.rate
+	}
+	l.last = now
+	l.tokens -= float64(n)
+	var sleep time.Duration
+	if l.tokens < 0 {
+		sleep = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+	if sleep > 0 {
+		time.Sleep(sleep)
+	}
+}
+
+// throttleChunk caps how many bytes one Read may consume, so a large
+// buffer fill becomes several short sleeps instead of one long stall.
+const throttleChunk = 64 * 1024
+
+// throttledReader charges every read against the shared bucket.
+type throttledReader struct {
+	r io.Reader
+	l *rateLimiter
+}
+
+func (t *throttledReader) Read(p []byte) (int, error) {
+	if len(p) > throttleChunk {
+		p = p[:throttleChunk]
+	}
+	n, err := t.r.Read(p)
+	if n > 0 {
+		t.l.wait(n)
+	}
+	return n, err
+}
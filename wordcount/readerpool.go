@@ -0,0 +1,118 @@
+package wordcount
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// chunkBuffering is how many read chunks may sit between a reader and
+// the tokenizer consuming them; small, so backpressure from a slow
+// tokenizer reaches the reader quickly.
+const chunkBuffering = 2
+
+// readerPool splits the worker into two stages: n reader goroutines —
+// sized for the storage device rather than the CPUs — open each input
+// and pump its raw bytes into a bounded chunk channel, and the
+// tokenizer workers downstream consume the chunks through an ordinary
+// io.Reader. Spinning disks want one or two readers, NVMe many, while
+// the tokenizer pool stays sized for the CPUs; the bounded channel
+// keeps either stage from running away from the other. The returned
+// queue feeds processInput unchanged: each staged Input's Open yields
+// the in-memory stream, and open failures surface through Open exactly
+// like direct file errors.
+func readerPool(ctx context.Context, workQueue <-chan Input, n, bufSize int) <-chan Input {
+	out := make(chan Input)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for in := range workQueue {
+				if ctx.Err() != nil {
+					continue
+				}
+				rc, err := in.Open()
+				if err != nil {
+					staged := Input{Name: in.Name, Open: func() (io.ReadCloser, error) { return nil, err }}
+					select {
+					case out <- staged:
+					case <-ctx.Done():
+					}
+					continue
+				}
+				ch := make(chan []byte, chunkBuffering)
+				staged := Input{Name: in.Name, Open: func() (io.ReadCloser, error) {
+					return &chunkStream{ch: ch}, nil
+				}}
+				select {
+				case out <- staged:
+				case <-ctx.Done():
+					rc.Close()
+					continue
+				}
+				for {
+					buf := getBuf(bufSize)
+					nr, rerr := rc.Read(buf)
+					sent := false
+					if nr > 0 {
+						select {
+						case ch <- buf[:nr]:
+							sent = true
+						case <-ctx.Done():
+							rerr = ctx.Err()
+						}
+					}
+					if !sent {
+						putBuf(buf)
+					}
+					if rerr != nil {
+						break
+					}
+				}
+				close(ch)
+				rc.Close()
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// chunkStream adapts a reader goroutine's chunk channel back into an
+// io.ReadCloser for the tokenizer, recycling each chunk once it has
+// been consumed. Close drains the channel so an early-exiting tokenizer
+// (a skipped binary, a read error) never leaves its reader blocked
+// mid-file.
+type chunkStream struct {
+	ch      <-chan []byte
+	cur     []byte // the chunk pending points into, for recycling
+	pending []byte
+}
+
+func (s *chunkStream) Read(p []byte) (int, error) {
+	for len(s.pending) == 0 {
+		putBuf(s.cur)
+		s.cur = nil
+		chunk, ok := <-s.ch
+		if !ok {
+			return 0, io.EOF
+		}
+		s.cur, s.pending = chunk, chunk
+	}
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+func (s *chunkStream) Close() error {
+	putBuf(s.cur)
+	s.cur, s.pending = nil, nil
+	for chunk := range s.ch {
+		putBuf(chunk)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package wordcount
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// The Redis sink speaks RESP directly over a TCP connection: the sink
+// only needs AUTH and HINCRBY, which is far less code than a client
+// library. Commands are pipelined so a large vocabulary costs round
+// trips in batches, not per word.
+
+// redisPipelineSize is how many commands are written before replies are
+// drained; it bounds the number of replies buffered server-side.
+const redisPipelineSize = 512
+
+// writeRedis increments per-word fields of a Redis hash with HINCRBY,
+// so repeated runs accumulate and a dashboard can read live counts with
+// HGETALL or build leaderboards from them. The destination is
+// addr/keyname (keyname defaults to "wordcounter"); per-file breakdowns
+// go to keyname:<file>. REDIS_PASSWORD authenticates when set.
+func writeRedis(rest string, res *Result) error {
+	addr, key, _ := strings.Cut(rest, "/")
+	if key == "" {
+		key = "wordcounter"
+	}
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	br := bufio.NewReader(conn)
+	bw := bufio.NewWriter(conn)
+
+	pending := 0
+	drain := func() error {
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+		for ; pending > 0; pending-- {
+			if err := readRedisReply(br); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	send := func(args ...string) error {
+		fmt.Fprintf(bw, "*%d\r\n", len(args))
+		for _, a := range args {
+			fmt.Fprintf(bw, "$%d\r\n%s\r\n", len(a), a)
+		}
+		pending++
+		if pending == redisPipelineSize {
+			return drain()
+		}
+		return nil
+	}
+
+	if pass := os.Getenv("REDIS_PASSWORD"); pass != "" {
+		if err := send("AUTH", pass); err != nil {
+			return err
+		}
+		if err := drain(); err != nil {
+			return err
+		}
+	}
+
+	for word, count := range res.Counts {
+		if err := send("HINCRBY", key, word, strconv.Itoa(count)); err != nil {
+			return err
+		}
+	}
+	for _, fr := range res.Files {
+		for word, count := range fr.Counts {
+			if err := send("HINCRBY", key+":"+fr.Name, word, strconv.Itoa(count)); err != nil {
+				return err
+			}
+		}
+	}
+	return drain()
+}
+
+// readRedisReply consumes one RESP reply, failing on Redis error
+// replies. Replies to HINCRBY are integers, but the full protocol is
+// small enough to handle.
+func readRedisReply(br *bufio.Reader) error {
+	line, err := br.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return fmt.Errorf("redis: empty reply")
+	}
+	switch line[0] {
+	case '+', ':':
+		return nil
+	case '-':
+		return fmt.Errorf("redis: %s", line[1:])
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return fmt.Errorf("redis: bad bulk length %q", line)
+		}
+		if n < 0 {
+			return nil // null bulk
+		}
+		_, err = io.CopyN(io.Discard, br, int64(n)+2) // body plus CRLF
+		return err
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return fmt.Errorf("redis: bad array length %q", line)
+		}
+		for i := 0; i < n; i++ {
+			if err := readRedisReply(br); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("redis: unexpected reply %q", line)
+}
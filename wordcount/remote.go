@@ -0,0 +1,265 @@
+package wordcount
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"word_counter/wordcount/remotepb"
+)
+
+// remoteOptions builds the wire form of opts: every field that can
+// change what gets counted, plus the tuning knobs a worker applies
+// locally. Compiled regexps do not survive serialization, so the
+// patterns travel as source text and are recompiled on the worker.
+// Funcs cannot travel at all — plugin mappers and reducers, and the
+// shared sketch modes, are rejected by Coordinate before any shard is
+// sent, so a distributed run either counts exactly like a local one or
+// fails up front. Call checkRemotable first; anything it does not
+// reject is carried here.
+func remoteOptions(opts Options) *remotepb.RemoteOptions {
+	ro := &remotepb.RemoteOptions{
+		Workers:            int32(opts.Workers),
+		BufSize:            int32(opts.BufSize),
+		MinLen:             int32(opts.MinLen),
+		MaxLen:             int32(opts.MaxLen),
+		CaseSensitive:      opts.CaseSensitive,
+		Locale:             opts.Locale,
+		Mode:               opts.Mode,
+		Segmenter:          opts.Segmenter,
+		Stem:               opts.Stem,
+		Lemmas:             opts.Lemmas,
+		MaxMem:             opts.MaxMem,
+		Ngram:              int32(opts.NGram),
+		CoWindow:           int32(opts.CoWindow),
+		ChunkSize:          opts.ChunkSize,
+		UseMmap:            opts.UseMmap,
+		IncludeDigits:      opts.IncludeDigits,
+		KeepApostrophes:    opts.KeepApostrophes,
+		ExpandContractions: opts.ExpandContractions,
+		Hyphens:            opts.Hyphens,
+		FoldDiacritics:     opts.FoldDiacritics,
+		CombinerFlush:      int32(opts.CombinerFlush),
+		CombinerFlushBytes: opts.CombinerFlushBytes,
+		MaxReadRate:        opts.MaxReadRate,
+		ReadWorkers:        int32(opts.ReadWorkers),
+		StdlibMap:          opts.StdlibMap,
+		Extract:            opts.Extract,
+		MarkdownCode:       opts.MarkdownCode,
+		CsvColumn:          opts.CSVColumn,
+		JsonField:          opts.JSONField,
+		Encoding:           opts.Encoding,
+		InvalidUtf8:        opts.InvalidUTF8,
+		Normalize:          opts.Normalize,
+		FetchTimeout:       int64(opts.FetchTimeout),
+		FetchLimit:         opts.FetchLimit,
+		MaxFileSize:        opts.MaxFileSize,
+		TruncateLarge:      opts.TruncateLarge,
+	}
+	for word := range opts.Stopwords {
+		ro.Stopwords = append(ro.Stopwords, word)
+	}
+	if opts.TokenRegex != nil {
+		ro.TokenRegex = opts.TokenRegex.String()
+	}
+	if opts.CountRegex != nil {
+		ro.CountRegex = opts.CountRegex.String()
+	}
+	return ro
+}
+
+// checkRemotable rejects options that cannot be reproduced on a remote
+// worker. Failing here beats shipping a lossy subset and letting
+// distributed runs quietly count differently than local ones.
+func checkRemotable(opts Options) error {
+	switch {
+	case opts.Mapper != nil || opts.Reducer != nil:
+		return fmt.Errorf("plugin mappers and reducers cannot be sent to remote workers")
+	case opts.Sketch != nil:
+		return fmt.Errorf("approximate counting (-approx cms) is not supported with remote workers")
+	case opts.Distinct != nil:
+		return fmt.Errorf("approximate counting (-approx hll) is not supported with remote workers")
+	}
+	return nil
+}
+
+// workerOptions rebuilds worker-side Options from the wire form.
+func workerOptions(ro *remotepb.RemoteOptions) (Options, error) {
+	opts := Options{
+		Workers:            int(ro.GetWorkers()),
+		BufSize:            int(ro.GetBufSize()),
+		MinLen:             int(ro.GetMinLen()),
+		MaxLen:             int(ro.GetMaxLen()),
+		CaseSensitive:      ro.GetCaseSensitive(),
+		Locale:             ro.GetLocale(),
+		Mode:               ro.GetMode(),
+		Segmenter:          ro.GetSegmenter(),
+		Stem:               ro.GetStem(),
+		Lemmas:             ro.GetLemmas(),
+		MaxMem:             ro.GetMaxMem(),
+		NGram:              int(ro.GetNgram()),
+		CoWindow:           int(ro.GetCoWindow()),
+		ChunkSize:          ro.GetChunkSize(),
+		UseMmap:            ro.GetUseMmap(),
+		IncludeDigits:      ro.GetIncludeDigits(),
+		KeepApostrophes:    ro.GetKeepApostrophes(),
+		ExpandContractions: ro.GetExpandContractions(),
+		Hyphens:            ro.GetHyphens(),
+		FoldDiacritics:     ro.GetFoldDiacritics(),
+		CombinerFlush:      int(ro.GetCombinerFlush()),
+		CombinerFlushBytes: ro.GetCombinerFlushBytes(),
+		MaxReadRate:        ro.GetMaxReadRate(),
+		ReadWorkers:        int(ro.GetReadWorkers()),
+		StdlibMap:          ro.GetStdlibMap(),
+		Extract:            ro.GetExtract(),
+		MarkdownCode:       ro.GetMarkdownCode(),
+		CSVColumn:          ro.GetCsvColumn(),
+		JSONField:          ro.GetJsonField(),
+		Encoding:           ro.GetEncoding(),
+		InvalidUTF8:        ro.GetInvalidUtf8(),
+		Normalize:          ro.GetNormalize(),
+		FetchTimeout:       time.Duration(ro.GetFetchTimeout()),
+		FetchLimit:         ro.GetFetchLimit(),
+		MaxFileSize:        ro.GetMaxFileSize(),
+		TruncateLarge:      ro.GetTruncateLarge(),
+	}
+	if words := ro.GetStopwords(); len(words) > 0 {
+		opts.Stopwords = make(map[string]struct{}, len(words))
+		for _, word := range words {
+			opts.Stopwords[word] = struct{}{}
+		}
+	}
+	if pat := ro.GetTokenRegex(); pat != "" {
+		re, err := regexp.Compile(pat)
+		if err != nil {
+			return opts, fmt.Errorf("invalid token regex from coordinator: %w", err)
+		}
+		opts.TokenRegex = re
+	}
+	if pat := ro.GetCountRegex(); pat != "" {
+		re, err := regexp.Compile(pat)
+		if err != nil {
+			return opts, fmt.Errorf("invalid count regex from coordinator: %w", err)
+		}
+		opts.CountRegex = re
+	}
+	return opts, nil
+}
+
+// Worker implements the gRPC counting service a worker process exposes.
+// Count runs the local MapReduce pipeline over the shard and returns
+// the partial map.
+type Worker struct {
+	remotepb.UnimplementedWorkerServer
+}
+
+// Count processes the job's paths locally and replies with the counts.
+func (Worker) Count(ctx context.Context, job *remotepb.CountJob) (*remotepb.CountReply, error) {
+	opts, err := workerOptions(job.GetOptions())
+	if err != nil {
+		return nil, err
+	}
+	counts, err := ProcessFiles(job.GetPaths(), opts)
+	if counts == nil {
+		return nil, err
+	}
+	if err != nil {
+		// Match local semantics: unreadable files are skipped, the
+		// rest of the shard still counts
+		log.Warnf("Shard completed with failures: %v", err)
+	}
+	reply := &remotepb.CountReply{Counts: make(map[string]int64, len(counts))}
+	for word, count := range counts {
+		reply.Counts[word] = int64(count)
+	}
+	return reply, nil
+}
+
+// ServeWorker listens on addr and serves Count requests over gRPC until
+// the listener fails. Workers count the files they are sent against
+// their own filesystem, so coordinator and workers are expected to
+// share storage (NFS, or identically laid-out local copies).
+func ServeWorker(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	srv := grpc.NewServer()
+	remotepb.RegisterWorkerServer(srv, Worker{})
+	log.Infof("Worker listening on %s", ln.Addr())
+	return srv.Serve(ln)
+}
+
+// Coordinate shards paths round-robin across the given worker addresses,
+// runs the shards concurrently, and reduces the partial maps into one
+// total. Workers process their shard with the full local pipeline, so the
+// reduce here only merges one map per worker.
+func Coordinate(workers []string, paths []string, opts Options) (map[string]int, error) {
+	if err := checkRemotable(opts); err != nil {
+		return nil, err
+	}
+	shards := make([][]string, len(workers))
+	for i, path := range paths {
+		slot := i % len(workers)
+		shards[slot] = append(shards[slot], path)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		total    = make(map[string]int)
+		firstErr error
+	)
+	ro := remoteOptions(opts)
+	for i, addr := range workers {
+		if len(shards[i]) == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(addr string, shard []string) {
+			defer wg.Done()
+			counts, err := callWorker(addr, &remotepb.CountJob{Paths: shard, Options: ro})
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("worker %s: %w", addr, err)
+				}
+				return
+			}
+			for word, count := range counts {
+				total[word] += count
+			}
+		}(addr, shards[i])
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return total, nil
+}
+
+// callWorker dials one worker and runs its shard to completion.
+func callWorker(addr string, job *remotepb.CountJob) (map[string]int, error) {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	reply, err := remotepb.NewWorkerClient(conn).Count(context.Background(), job)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(reply.GetCounts()))
+	for word, count := range reply.GetCounts() {
+		counts[word] = int(count)
+	}
+	return counts, nil
+}
@@ -0,0 +1,701 @@
+// Wire definition for the distributed counting service: a coordinator
+// shards file paths across workers and each worker replies with the
+// partial counts for its shard. Regenerate the Go stubs with:
+//
+//	protoc --go_out=. --go_opt=paths=source_relative \
+//	       --go-grpc_out=. --go-grpc_opt=paths=source_relative remote.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: remote.proto
+
+package remotepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// CountJob is one shard of work: the paths the worker should count
+// (which must be readable on the worker's filesystem) and the counting
+// options.
+type CountJob struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Paths   []string       `protobuf:"bytes,1,rep,name=paths,proto3" json:"paths,omitempty"`
+	Options *RemoteOptions `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+}
+
+func (x *CountJob) Reset() {
+	*x = CountJob{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CountJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountJob) ProtoMessage() {}
+
+func (x *CountJob) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountJob.ProtoReflect.Descriptor instead.
+func (*CountJob) Descriptor() ([]byte, []int) {
+	return file_remote_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CountJob) GetPaths() []string {
+	if x != nil {
+		return x.Paths
+	}
+	return nil
+}
+
+func (x *CountJob) GetOptions() *RemoteOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+// CountReply carries the shard's partial word counts back to the
+// coordinator.
+type CountReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Counts map[string]int64 `protobuf:"bytes,1,rep,name=counts,proto3" json:"counts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+}
+
+func (x *CountReply) Reset() {
+	*x = CountReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CountReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountReply) ProtoMessage() {}
+
+func (x *CountReply) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountReply.ProtoReflect.Descriptor instead.
+func (*CountReply) Descriptor() ([]byte, []int) {
+	return file_remote_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CountReply) GetCounts() map[string]int64 {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+// RemoteOptions is the wire form of the coordinator's Options: every
+// field that can change what gets counted, plus the tuning knobs a
+// worker applies locally. Regex patterns travel as source text and are
+// recompiled on the worker; options that cannot travel at all (plugin
+// mappers and reducers, the shared sketch modes) are rejected by the
+// coordinator before any shard is sent.
+type RemoteOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Workers            int32             `protobuf:"varint,1,opt,name=workers,proto3" json:"workers,omitempty"`
+	BufSize            int32             `protobuf:"varint,2,opt,name=buf_size,json=bufSize,proto3" json:"buf_size,omitempty"`
+	Stopwords          []string          `protobuf:"bytes,3,rep,name=stopwords,proto3" json:"stopwords,omitempty"`
+	MinLen             int32             `protobuf:"varint,4,opt,name=min_len,json=minLen,proto3" json:"min_len,omitempty"`
+	MaxLen             int32             `protobuf:"varint,5,opt,name=max_len,json=maxLen,proto3" json:"max_len,omitempty"`
+	CaseSensitive      bool              `protobuf:"varint,6,opt,name=case_sensitive,json=caseSensitive,proto3" json:"case_sensitive,omitempty"`
+	Locale             string            `protobuf:"bytes,7,opt,name=locale,proto3" json:"locale,omitempty"`
+	Mode               string            `protobuf:"bytes,8,opt,name=mode,proto3" json:"mode,omitempty"`
+	Segmenter          string            `protobuf:"bytes,9,opt,name=segmenter,proto3" json:"segmenter,omitempty"`
+	Stem               string            `protobuf:"bytes,10,opt,name=stem,proto3" json:"stem,omitempty"`
+	Lemmas             map[string]string `protobuf:"bytes,11,rep,name=lemmas,proto3" json:"lemmas,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	MaxMem             int64             `protobuf:"varint,12,opt,name=max_mem,json=maxMem,proto3" json:"max_mem,omitempty"`
+	Ngram              int32             `protobuf:"varint,13,opt,name=ngram,proto3" json:"ngram,omitempty"`
+	CoWindow           int32             `protobuf:"varint,14,opt,name=co_window,json=coWindow,proto3" json:"co_window,omitempty"`
+	ChunkSize          int64             `protobuf:"varint,15,opt,name=chunk_size,json=chunkSize,proto3" json:"chunk_size,omitempty"`
+	UseMmap            bool              `protobuf:"varint,16,opt,name=use_mmap,json=useMmap,proto3" json:"use_mmap,omitempty"`
+	IncludeDigits      bool              `protobuf:"varint,17,opt,name=include_digits,json=includeDigits,proto3" json:"include_digits,omitempty"`
+	KeepApostrophes    bool              `protobuf:"varint,18,opt,name=keep_apostrophes,json=keepApostrophes,proto3" json:"keep_apostrophes,omitempty"`
+	ExpandContractions bool              `protobuf:"varint,19,opt,name=expand_contractions,json=expandContractions,proto3" json:"expand_contractions,omitempty"`
+	Hyphens            string            `protobuf:"bytes,20,opt,name=hyphens,proto3" json:"hyphens,omitempty"`
+	FoldDiacritics     bool              `protobuf:"varint,21,opt,name=fold_diacritics,json=foldDiacritics,proto3" json:"fold_diacritics,omitempty"`
+	CombinerFlush      int32             `protobuf:"varint,22,opt,name=combiner_flush,json=combinerFlush,proto3" json:"combiner_flush,omitempty"`
+	CombinerFlushBytes int64             `protobuf:"varint,23,opt,name=combiner_flush_bytes,json=combinerFlushBytes,proto3" json:"combiner_flush_bytes,omitempty"`
+	MaxReadRate        int64             `protobuf:"varint,24,opt,name=max_read_rate,json=maxReadRate,proto3" json:"max_read_rate,omitempty"`
+	ReadWorkers        int32             `protobuf:"varint,25,opt,name=read_workers,json=readWorkers,proto3" json:"read_workers,omitempty"`
+	StdlibMap          bool              `protobuf:"varint,26,opt,name=stdlib_map,json=stdlibMap,proto3" json:"stdlib_map,omitempty"`
+	TokenRegex         string            `protobuf:"bytes,27,opt,name=token_regex,json=tokenRegex,proto3" json:"token_regex,omitempty"`
+	CountRegex         string            `protobuf:"bytes,28,opt,name=count_regex,json=countRegex,proto3" json:"count_regex,omitempty"`
+	Extract            string            `protobuf:"bytes,29,opt,name=extract,proto3" json:"extract,omitempty"`
+	MarkdownCode       bool              `protobuf:"varint,30,opt,name=markdown_code,json=markdownCode,proto3" json:"markdown_code,omitempty"`
+	CsvColumn          string            `protobuf:"bytes,31,opt,name=csv_column,json=csvColumn,proto3" json:"csv_column,omitempty"`
+	JsonField          string            `protobuf:"bytes,32,opt,name=json_field,json=jsonField,proto3" json:"json_field,omitempty"`
+	Encoding           string            `protobuf:"bytes,33,opt,name=encoding,proto3" json:"encoding,omitempty"`
+	InvalidUtf8        string            `protobuf:"bytes,34,opt,name=invalid_utf8,json=invalidUtf8,proto3" json:"invalid_utf8,omitempty"`
+	Normalize          string            `protobuf:"bytes,35,opt,name=normalize,proto3" json:"normalize,omitempty"`
+	// FetchTimeout in nanoseconds (a Go time.Duration).
+	FetchTimeout  int64 `protobuf:"varint,36,opt,name=fetch_timeout,json=fetchTimeout,proto3" json:"fetch_timeout,omitempty"`
+	FetchLimit    int64 `protobuf:"varint,37,opt,name=fetch_limit,json=fetchLimit,proto3" json:"fetch_limit,omitempty"`
+	MaxFileSize   int64 `protobuf:"varint,38,opt,name=max_file_size,json=maxFileSize,proto3" json:"max_file_size,omitempty"`
+	TruncateLarge bool  `protobuf:"varint,39,opt,name=truncate_large,json=truncateLarge,proto3" json:"truncate_large,omitempty"`
+}
+
+func (x *RemoteOptions) Reset() {
+	*x = RemoteOptions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RemoteOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoteOptions) ProtoMessage() {}
+
+func (x *RemoteOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoteOptions.ProtoReflect.Descriptor instead.
+func (*RemoteOptions) Descriptor() ([]byte, []int) {
+	return file_remote_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RemoteOptions) GetWorkers() int32 {
+	if x != nil {
+		return x.Workers
+	}
+	return 0
+}
+
+func (x *RemoteOptions) GetBufSize() int32 {
+	if x != nil {
+		return x.BufSize
+	}
+	return 0
+}
+
+func (x *RemoteOptions) GetStopwords() []string {
+	if x != nil {
+		return x.Stopwords
+	}
+	return nil
+}
+
+func (x *RemoteOptions) GetMinLen() int32 {
+	if x != nil {
+		return x.MinLen
+	}
+	return 0
+}
+
+func (x *RemoteOptions) GetMaxLen() int32 {
+	if x != nil {
+		return x.MaxLen
+	}
+	return 0
+}
+
+func (x *RemoteOptions) GetCaseSensitive() bool {
+	if x != nil {
+		return x.CaseSensitive
+	}
+	return false
+}
+
+func (x *RemoteOptions) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *RemoteOptions) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *RemoteOptions) GetSegmenter() string {
+	if x != nil {
+		return x.Segmenter
+	}
+	return ""
+}
+
+func (x *RemoteOptions) GetStem() string {
+	if x != nil {
+		return x.Stem
+	}
+	return ""
+}
+
+func (x *RemoteOptions) GetLemmas() map[string]string {
+	if x != nil {
+		return x.Lemmas
+	}
+	return nil
+}
+
+func (x *RemoteOptions) GetMaxMem() int64 {
+	if x != nil {
+		return x.MaxMem
+	}
+	return 0
+}
+
+func (x *RemoteOptions) GetNgram() int32 {
+	if x != nil {
+		return x.Ngram
+	}
+	return 0
+}
+
+func (x *RemoteOptions) GetCoWindow() int32 {
+	if x != nil {
+		return x.CoWindow
+	}
+	return 0
+}
+
+func (x *RemoteOptions) GetChunkSize() int64 {
+	if x != nil {
+		return x.ChunkSize
+	}
+	return 0
+}
+
+func (x *RemoteOptions) GetUseMmap() bool {
+	if x != nil {
+		return x.UseMmap
+	}
+	return false
+}
+
+func (x *RemoteOptions) GetIncludeDigits() bool {
+	if x != nil {
+		return x.IncludeDigits
+	}
+	return false
+}
+
+func (x *RemoteOptions) GetKeepApostrophes() bool {
+	if x != nil {
+		return x.KeepApostrophes
+	}
+	return false
+}
+
+func (x *RemoteOptions) GetExpandContractions() bool {
+	if x != nil {
+		return x.ExpandContractions
+	}
+	return false
+}
+
+func (x *RemoteOptions) GetHyphens() string {
+	if x != nil {
+		return x.Hyphens
+	}
+	return ""
+}
+
+func (x *RemoteOptions) GetFoldDiacritics() bool {
+	if x != nil {
+		return x.FoldDiacritics
+	}
+	return false
+}
+
+func (x *RemoteOptions) GetCombinerFlush() int32 {
+	if x != nil {
+		return x.CombinerFlush
+	}
+	return 0
+}
+
+func (x *RemoteOptions) GetCombinerFlushBytes() int64 {
+	if x != nil {
+		return x.CombinerFlushBytes
+	}
+	return 0
+}
+
+func (x *RemoteOptions) GetMaxReadRate() int64 {
+	if x != nil {
+		return x.MaxReadRate
+	}
+	return 0
+}
+
+func (x *RemoteOptions) GetReadWorkers() int32 {
+	if x != nil {
+		return x.ReadWorkers
+	}
+	return 0
+}
+
+func (x *RemoteOptions) GetStdlibMap() bool {
+	if x != nil {
+		return x.StdlibMap
+	}
+	return false
+}
+
+func (x *RemoteOptions) GetTokenRegex() string {
+	if x != nil {
+		return x.TokenRegex
+	}
+	return ""
+}
+
+func (x *RemoteOptions) GetCountRegex() string {
+	if x != nil {
+		return x.CountRegex
+	}
+	return ""
+}
+
+func (x *RemoteOptions) GetExtract() string {
+	if x != nil {
+		return x.Extract
+	}
+	return ""
+}
+
+func (x *RemoteOptions) GetMarkdownCode() bool {
+	if x != nil {
+		return x.MarkdownCode
+	}
+	return false
+}
+
+func (x *RemoteOptions) GetCsvColumn() string {
+	if x != nil {
+		return x.CsvColumn
+	}
+	return ""
+}
+
+func (x *RemoteOptions) GetJsonField() string {
+	if x != nil {
+		return x.JsonField
+	}
+	return ""
+}
+
+func (x *RemoteOptions) GetEncoding() string {
+	if x != nil {
+		return x.Encoding
+	}
+	return ""
+}
+
+func (x *RemoteOptions) GetInvalidUtf8() string {
+	if x != nil {
+		return x.InvalidUtf8
+	}
+	return ""
+}
+
+func (x *RemoteOptions) GetNormalize() string {
+	if x != nil {
+		return x.Normalize
+	}
+	return ""
+}
+
+func (x *RemoteOptions) GetFetchTimeout() int64 {
+	if x != nil {
+		return x.FetchTimeout
+	}
+	return 0
+}
+
+func (x *RemoteOptions) GetFetchLimit() int64 {
+	if x != nil {
+		return x.FetchLimit
+	}
+	return 0
+}
+
+func (x *RemoteOptions) GetMaxFileSize() int64 {
+	if x != nil {
+		return x.MaxFileSize
+	}
+	return 0
+}
+
+func (x *RemoteOptions) GetTruncateLarge() bool {
+	if x != nil {
+		return x.TruncateLarge
+	}
+	return false
+}
+
+var File_remote_proto protoreflect.FileDescriptor
+
+var file_remote_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08,
+	0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x70, 0x62, 0x22, 0x53, 0x0a, 0x08, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x4a, 0x6f, 0x62, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x12, 0x31, 0x0a, 0x07, 0x6f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x72, 0x65,
+	0x6d, 0x6f, 0x74, 0x65, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x81, 0x01,
+	0x0a, 0x0a, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x38, 0x0a, 0x06,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x72,
+	0x65, 0x6d, 0x6f, 0x74, 0x65, 0x70, 0x62, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x1a, 0x39, 0x0a, 0x0b, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
+	0x01, 0x22, 0xc9, 0x0a, 0x0a, 0x0d, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x4f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x12, 0x19, 0x0a,
+	0x08, 0x62, 0x75, 0x66, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x07, 0x62, 0x75, 0x66, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x74, 0x6f, 0x70,
+	0x77, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x73, 0x74, 0x6f,
+	0x70, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x6d, 0x69, 0x6e, 0x5f, 0x6c, 0x65,
+	0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6d, 0x69, 0x6e, 0x4c, 0x65, 0x6e, 0x12,
+	0x17, 0x0a, 0x07, 0x6d, 0x61, 0x78, 0x5f, 0x6c, 0x65, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x06, 0x6d, 0x61, 0x78, 0x4c, 0x65, 0x6e, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x61, 0x73, 0x65,
+	0x5f, 0x73, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0d, 0x63, 0x61, 0x73, 0x65, 0x53, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x73,
+	0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x65, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x74, 0x65,
+	0x6d, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x3b, 0x0a,
+	0x06, 0x6c, 0x65, 0x6d, 0x6d, 0x61, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e,
+	0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x4f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x4c, 0x65, 0x6d, 0x6d, 0x61, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x52, 0x06, 0x6c, 0x65, 0x6d, 0x6d, 0x61, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x6d, 0x61,
+	0x78, 0x5f, 0x6d, 0x65, 0x6d, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6d, 0x61, 0x78,
+	0x4d, 0x65, 0x6d, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x67, 0x72, 0x61, 0x6d, 0x18, 0x0d, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x05, 0x6e, 0x67, 0x72, 0x61, 0x6d, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x5f,
+	0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x6f,
+	0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x5f,
+	0x73, 0x69, 0x7a, 0x65, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x63, 0x68, 0x75, 0x6e,
+	0x6b, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x5f, 0x6d, 0x6d, 0x61,
+	0x70, 0x18, 0x10, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x75, 0x73, 0x65, 0x4d, 0x6d, 0x61, 0x70,
+	0x12, 0x25, 0x0a, 0x0e, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x69,
+	0x74, 0x73, 0x18, 0x11, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64,
+	0x65, 0x44, 0x69, 0x67, 0x69, 0x74, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x6b, 0x65, 0x65, 0x70, 0x5f,
+	0x61, 0x70, 0x6f, 0x73, 0x74, 0x72, 0x6f, 0x70, 0x68, 0x65, 0x73, 0x18, 0x12, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0f, 0x6b, 0x65, 0x65, 0x70, 0x41, 0x70, 0x6f, 0x73, 0x74, 0x72, 0x6f, 0x70, 0x68,
+	0x65, 0x73, 0x12, 0x2f, 0x0a, 0x13, 0x65, 0x78, 0x70, 0x61, 0x6e, 0x64, 0x5f, 0x63, 0x6f, 0x6e,
+	0x74, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x13, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x12, 0x65, 0x78, 0x70, 0x61, 0x6e, 0x64, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x68, 0x79, 0x70, 0x68, 0x65, 0x6e, 0x73, 0x18, 0x14,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x68, 0x79, 0x70, 0x68, 0x65, 0x6e, 0x73, 0x12, 0x27, 0x0a,
+	0x0f, 0x66, 0x6f, 0x6c, 0x64, 0x5f, 0x64, 0x69, 0x61, 0x63, 0x72, 0x69, 0x74, 0x69, 0x63, 0x73,
+	0x18, 0x15, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x66, 0x6f, 0x6c, 0x64, 0x44, 0x69, 0x61, 0x63,
+	0x72, 0x69, 0x74, 0x69, 0x63, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x62, 0x69, 0x6e,
+	0x65, 0x72, 0x5f, 0x66, 0x6c, 0x75, 0x73, 0x68, 0x18, 0x16, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d,
+	0x63, 0x6f, 0x6d, 0x62, 0x69, 0x6e, 0x65, 0x72, 0x46, 0x6c, 0x75, 0x73, 0x68, 0x12, 0x30, 0x0a,
+	0x14, 0x63, 0x6f, 0x6d, 0x62, 0x69, 0x6e, 0x65, 0x72, 0x5f, 0x66, 0x6c, 0x75, 0x73, 0x68, 0x5f,
+	0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x17, 0x20, 0x01, 0x28, 0x03, 0x52, 0x12, 0x63, 0x6f, 0x6d,
+	0x62, 0x69, 0x6e, 0x65, 0x72, 0x46, 0x6c, 0x75, 0x73, 0x68, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12,
+	0x22, 0x0a, 0x0d, 0x6d, 0x61, 0x78, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x72, 0x61, 0x74, 0x65,
+	0x18, 0x18, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x52, 0x65, 0x61, 0x64, 0x52,
+	0x61, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x77, 0x6f, 0x72, 0x6b,
+	0x65, 0x72, 0x73, 0x18, 0x19, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x72, 0x65, 0x61, 0x64, 0x57,
+	0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x64, 0x6c, 0x69, 0x62,
+	0x5f, 0x6d, 0x61, 0x70, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x73, 0x74, 0x64, 0x6c,
+	0x69, 0x62, 0x4d, 0x61, 0x70, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x5f, 0x72,
+	0x65, 0x67, 0x65, 0x78, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x52, 0x65, 0x67, 0x65, 0x78, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f,
+	0x72, 0x65, 0x67, 0x65, 0x78, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x52, 0x65, 0x67, 0x65, 0x78, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x78, 0x74, 0x72, 0x61,
+	0x63, 0x74, 0x18, 0x1d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x65, 0x78, 0x74, 0x72, 0x61, 0x63,
+	0x74, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x61, 0x72, 0x6b, 0x64, 0x6f, 0x77, 0x6e, 0x5f, 0x63, 0x6f,
+	0x64, 0x65, 0x18, 0x1e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x6d, 0x61, 0x72, 0x6b, 0x64, 0x6f,
+	0x77, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x73, 0x76, 0x5f, 0x63, 0x6f,
+	0x6c, 0x75, 0x6d, 0x6e, 0x18, 0x1f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x73, 0x76, 0x43,
+	0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x6a, 0x73, 0x6f, 0x6e, 0x5f, 0x66, 0x69,
+	0x65, 0x6c, 0x64, 0x18, 0x20, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6a, 0x73, 0x6f, 0x6e, 0x46,
+	0x69, 0x65, 0x6c, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67,
+	0x18, 0x21, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67,
+	0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x5f, 0x75, 0x74, 0x66, 0x38,
+	0x18, 0x22, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x6e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x55,
+	0x74, 0x66, 0x38, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x69, 0x7a, 0x65,
+	0x18, 0x23, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x69, 0x7a,
+	0x65, 0x12, 0x23, 0x0a, 0x0d, 0x66, 0x65, 0x74, 0x63, 0x68, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f,
+	0x75, 0x74, 0x18, 0x24, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x66, 0x65, 0x74, 0x63, 0x68, 0x54,
+	0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x65, 0x74, 0x63, 0x68, 0x5f,
+	0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x25, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x66, 0x65, 0x74,
+	0x63, 0x68, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x22, 0x0a, 0x0d, 0x6d, 0x61, 0x78, 0x5f, 0x66,
+	0x69, 0x6c, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x26, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b,
+	0x6d, 0x61, 0x78, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x74,
+	0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x5f, 0x6c, 0x61, 0x72, 0x67, 0x65, 0x18, 0x27, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0d, 0x74, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x4c, 0x61, 0x72,
+	0x67, 0x65, 0x1a, 0x39, 0x0a, 0x0b, 0x4c, 0x65, 0x6d, 0x6d, 0x61, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x32, 0x3b, 0x0a,
+	0x06, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x12, 0x31, 0x0a, 0x05, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x12, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x70, 0x62, 0x2e, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x4a, 0x6f, 0x62, 0x1a, 0x14, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x70, 0x62, 0x2e,
+	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x42, 0x21, 0x5a, 0x1f, 0x77, 0x6f,
+	0x72, 0x64, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x2f, 0x77, 0x6f, 0x72, 0x64, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x2f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x70, 0x62, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_remote_proto_rawDescOnce sync.Once
+	file_remote_proto_rawDescData = file_remote_proto_rawDesc
+)
+
+func file_remote_proto_rawDescGZIP() []byte {
+	file_remote_proto_rawDescOnce.Do(func() {
+		file_remote_proto_rawDescData = protoimpl.X.CompressGZIP(file_remote_proto_rawDescData)
+	})
+	return file_remote_proto_rawDescData
+}
+
+var file_remote_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_remote_proto_goTypes = []any{
+	(*CountJob)(nil),      // 0: remotepb.CountJob
+	(*CountReply)(nil),    // 1: remotepb.CountReply
+	(*RemoteOptions)(nil), // 2: remotepb.RemoteOptions
+	nil,                   // 3: remotepb.CountReply.CountsEntry
+	nil,                   // 4: remotepb.RemoteOptions.LemmasEntry
+}
+var file_remote_proto_depIdxs = []int32{
+	2, // 0: remotepb.CountJob.options:type_name -> remotepb.RemoteOptions
+	3, // 1: remotepb.CountReply.counts:type_name -> remotepb.CountReply.CountsEntry
+	4, // 2: remotepb.RemoteOptions.lemmas:type_name -> remotepb.RemoteOptions.LemmasEntry
+	0, // 3: remotepb.Worker.Count:input_type -> remotepb.CountJob
+	1, // 4: remotepb.Worker.Count:output_type -> remotepb.CountReply
+	4, // [4:5] is the sub-list for method output_type
+	3, // [3:4] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_remote_proto_init() }
+func file_remote_proto_init() {
+	if File_remote_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_remote_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*CountJob); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_remote_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*CountReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_remote_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*RemoteOptions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_remote_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_remote_proto_goTypes,
+		DependencyIndexes: file_remote_proto_depIdxs,
+		MessageInfos:      file_remote_proto_msgTypes,
+	}.Build()
+	File_remote_proto = out.File
+	file_remote_proto_rawDesc = nil
+	file_remote_proto_goTypes = nil
+	file_remote_proto_depIdxs = nil
+}
@@ -0,0 +1,241 @@
+package wordcount
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3 access is a thin REST client rather than the AWS SDK: listing a
+// prefix and streaming objects takes two request shapes plus SigV4
+// signing, which is less code than the SDK's dependency tree.
+// Credentials come from the usual AWS_ACCESS_KEY_ID / AWS_SECRET_ACCESS_KEY
+// / AWS_SESSION_TOKEN environment variables; without them requests go
+// unsigned, which suffices for public buckets. AWS_ENDPOINT_URL points
+// the client at an S3-compatible store such as MinIO or LocalStack.
+
+// s3Client issues anonymous or SigV4-signed requests against one bucket.
+// It implements objectStore.
+type s3Client struct {
+	bucket    string
+	region    string
+	endpoint  string // custom endpoint (MinIO, LocalStack); empty for AWS
+	accessKey string
+	secretKey string
+	token     string
+}
+
+// newS3Client resolves region, endpoint, and credentials from the
+// standard AWS environment variables.
+func newS3Client(bucket string) *s3Client {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &s3Client{
+		bucket:    bucket,
+		region:    region,
+		endpoint:  os.Getenv("AWS_ENDPOINT_URL"),
+		accessKey: os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		token:     os.Getenv("AWS_SESSION_TOKEN"),
+	}
+}
+
+// url builds the request URL for a key ("" addresses the bucket itself):
+// virtual-hosted style against AWS, path style against custom endpoints.
+func (c *s3Client) url(key string) string {
+	if c.endpoint != "" {
+		return strings.TrimSuffix(c.endpoint, "/") + "/" + c.bucket + "/" + uriEscape(key)
+	}
+	return "https://" + c.bucket + ".s3." + c.region + ".amazonaws.com/" + uriEscape(key)
+}
+
+// do issues a signed GET and fails on any non-200 response, folding the
+// first part of S3's XML error body into the error.
+func (c *s3Client) do(key string, query url.Values) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, c.url(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	// The canonical query string is also what gets sent, so the
+	// signature always matches the wire bytes
+	req.URL.RawQuery = canonicalQuery(query)
+	c.sign(req)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		return nil, fmt.Errorf("s3://%s: %s: %s", c.bucket, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+// list returns the keys under prefix, following continuation tokens
+// through buckets larger than one ListObjectsV2 page.
+func (c *s3Client) list(prefix string) ([]string, error) {
+	var keys []string
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}}
+		if prefix != "" {
+			query.Set("prefix", prefix)
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		resp, err := c.do("", query)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Contents []struct {
+				Key string `xml:"Key"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("s3://%s: listing %q: %w", c.bucket, prefix, err)
+		}
+		for _, obj := range page.Contents {
+			keys = append(keys, obj.Key)
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return keys, nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+// get streams one object's body.
+func (c *s3Client) get(key string) (io.ReadCloser, error) {
+	resp, err := c.do(key, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// emptyPayloadSHA256 is the SHA-256 of an empty body; every request here
+// is a bodiless GET.
+const emptyPayloadSHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// sign adds an AWS Signature Version 4 Authorization header. With no
+// credentials configured the request goes out anonymous.
+func (c *s3Client) sign(req *http.Request) {
+	if c.accessKey == "" || c.secretKey == "" {
+		return
+	}
+	signV4(req, time.Now().UTC(), c.region, c.accessKey, c.secretKey, c.token)
+}
+
+// signV4 performs the actual SigV4 canonicalization and signing at the
+// given time, split from sign so tests can check the output against the
+// published AWS vectors.
+func signV4(req *http.Request, now time.Time, region, accessKey, secretKey, token string) {
+	amzDate := now.Format("20060102T150405Z")
+	scopeDate := now.Format("20060102")
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", emptyPayloadSHA256)
+	headers := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if token != "" {
+		req.Header.Set("x-amz-security-token", token)
+		headers = append(headers, "x-amz-security-token")
+	}
+
+	var canonHeaders strings.Builder
+	for _, h := range headers {
+		value := req.Header.Get(h)
+		if h == "host" {
+			value = req.URL.Host
+		}
+		canonHeaders.WriteString(h + ":" + value + "\n")
+	}
+	signedHeaders := strings.Join(headers, ";")
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonHeaders.String(),
+		signedHeaders,
+		emptyPayloadSHA256,
+	}, "\n")
+
+	scope := scopeDate + "/" + region + "/s3/aws4_request"
+	sum := sha256.Sum256([]byte(canonical))
+	toSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hex.EncodeToString(sum[:])}, "\n")
+	key := hmacSHA256([]byte("AWS4"+secretKey), scopeDate)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+// hmacSHA256 is one link of the SigV4 key derivation chain.
+func hmacSHA256(key []byte, msg string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(msg))
+	return mac.Sum(nil)
+}
+
+// canonicalQuery encodes query parameters the way SigV4 canonicalizes
+// them: keys sorted, every byte outside the RFC 3986 unreserved set
+// percent-encoded (url.Values.Encode uses + for spaces, which S3 rejects
+// in signatures).
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		for _, v := range query[k] {
+			if b.Len() > 0 {
+				b.WriteByte('&')
+			}
+			b.WriteString(strings.ReplaceAll(uriEscape(k), "/", "%2F"))
+			b.WriteByte('=')
+			b.WriteString(strings.ReplaceAll(uriEscape(v), "/", "%2F"))
+		}
+	}
+	return b.String()
+}
+
+// uriEscape percent-encodes everything outside the RFC 3986 unreserved
+// set, keeping "/" so object keys stay readable path segments.
+func uriEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case ch >= 'A' && ch <= 'Z', ch >= 'a' && ch <= 'z', ch >= '0' && ch <= '9',
+			ch == '-', ch == '.', ch == '_', ch == '~', ch == '/':
+			b.WriteByte(ch)
+		default:
+			fmt.Fprintf(&b, "%%%02X", ch)
+		}
+	}
+	return b.String()
+}
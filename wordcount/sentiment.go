@@ -0,0 +1,114 @@
+package wordcount
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Lexicon maps words to AFINN-style sentiment scores, negative for
+// unpleasant words and positive for pleasant ones (typically -5..5).
+type Lexicon map[string]int
+
+// LoadLexicon reads a sentiment word list: one "word<TAB>score" (or
+// space-separated) entry per line, blank lines and # comments ignored —
+// the format the AFINN lists ship in, so they drop in unmodified.
+func LoadLexicon(path string) (Lexicon, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	lex := make(Lexicon)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%s: bad lexicon line %q: want word and score", path, line)
+		}
+		score, err := strconv.Atoi(fields[len(fields)-1])
+		if err != nil {
+			return nil, fmt.Errorf("%s: bad score in %q: %v", path, line, err)
+		}
+		// Multi-word entries ("cool stuff") only match -ngram output;
+		// single words cover the common case
+		lex[strings.ToLower(strings.Join(fields[:len(fields)-1], " "))] = score
+	}
+	return lex, scanner.Err()
+}
+
+// BuiltinLexicon returns a small general-purpose lexicon drawn from the
+// strongest and most frequent AFINN entries, enough for a quick signal
+// without shipping a data file. Serious analysis should load a full
+// list with LoadLexicon.
+func BuiltinLexicon() Lexicon {
+	return Lexicon{
+		"abandoned": -2, "amazing": 4, "angry": -3, "awesome": 4,
+		"awful": -3, "bad": -3, "beautiful": 3, "best": 3, "better": 2,
+		"boring": -3, "broken": -1, "brilliant": 4, "catastrophic": -4,
+		"crash": -2, "dead": -3, "delighted": 3, "disappointed": -2,
+		"disaster": -2, "dreadful": -3, "error": -2, "excellent": 3,
+		"fail": -2, "failed": -2, "fantastic": 4, "fine": 2, "fraud": -4,
+		"good": 3, "great": 3, "happy": 3, "hate": -3, "horrible": -3,
+		"hurt": -2, "impressive": 3, "love": 3, "lovely": 3, "miss": -2,
+		"nice": 3, "outstanding": 5, "perfect": 3, "pleased": 3,
+		"poor": -2, "problem": -2, "sad": -2, "scam": -2, "success": 2,
+		"superb": 5, "terrible": -3, "thanks": 2, "ugly": -3, "useful": 2,
+		"useless": -2, "win": 4, "wonderful": 4, "worst": -3, "wrong": -2,
+	}
+}
+
+// Sentiment summarizes a count map scored against a lexicon.
+type Sentiment struct {
+	Score   int64 // sum of score × count over matched words
+	Matched int64 // tokens that appeared in the lexicon
+	Tokens  int64 // all tokens
+}
+
+// Mean is the average score per matched token, the comparable number
+// across files of different sizes; zero when nothing matched.
+func (s Sentiment) Mean() float64 {
+	if s.Matched == 0 {
+		return 0
+	}
+	return float64(s.Score) / float64(s.Matched)
+}
+
+// ScoreCounts scores one count map against a lexicon.
+func ScoreCounts(counts map[string]int, lex Lexicon) Sentiment {
+	var s Sentiment
+	for word, c := range counts {
+		s.Tokens += int64(c)
+		if score, ok := lex[word]; ok {
+			s.Matched += int64(c)
+			s.Score += int64(score) * int64(c)
+		}
+	}
+	return s
+}
+
+// WriteSentiment reports per-file sentiment plus the aggregate,
+// alongside whatever counts the run prints. The per-file rows need
+// Options.PerFile.
+func WriteSentiment(w io.Writer, files []FileResult, total map[string]int, lex Lexicon) error {
+	if _, err := fmt.Fprintf(w, "%8s %8s %10s %8s  %s\n", "Score", "Matched", "Tokens", "Mean", "File"); err != nil {
+		return err
+	}
+	for _, fr := range files {
+		s := ScoreCounts(fr.Counts, lex)
+		if _, err := fmt.Fprintf(w, "%+8d %8d %10d %+8.2f  %s\n", s.Score, s.Matched, s.Tokens, s.Mean(), fr.Name); err != nil {
+			return err
+		}
+	}
+	s := ScoreCounts(total, lex)
+	_, err := fmt.Fprintf(w, "%+8d %8d %10d %+8.2f  %s\n", s.Score, s.Matched, s.Tokens, s.Mean(), "total")
+	return err
+}
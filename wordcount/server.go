@@ -0,0 +1,192 @@
+package wordcount
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Job statuses reported by the HTTP API.
+const (
+	JobPending = "pending"
+	JobRunning = "running"
+	JobDone    = "done"
+	JobFailed  = "failed"
+)
+
+// Job is one counting request submitted to the HTTP server. Counts is
+// populated once Status reaches done.
+type Job struct {
+	ID        string         `json:"id"`
+	Status    string         `json:"status"`
+	Error     string         `json:"error,omitempty"`
+	Submitted time.Time      `json:"submitted"`
+	Counts    map[string]int `json:"-"`
+}
+
+// jobRequest is the JSON body accepted by POST /jobs.
+type jobRequest struct {
+	Paths          []string `json:"paths"`
+	Recursive      bool     `json:"recursive"`
+	FollowSymlinks bool     `json:"follow_symlinks"`
+}
+
+// Server runs the word counter as a long-lived HTTP service: clients
+// POST a job, poll its status, and fetch the counts as JSON once done.
+// Every job is counted with the Options the server was started with.
+type Server struct {
+	opts   Options
+	mu     sync.Mutex
+	jobs   map[string]*Job
+	nextID int
+}
+
+// NewServer returns a Server that counts jobs with the given options.
+func NewServer(opts Options) *Server {
+	return &Server{opts: opts, jobs: make(map[string]*Job)}
+}
+
+// Handler returns the HTTP routes:
+//
+//	POST /jobs           submit a job: {"paths": [...]} or a raw text body
+//	GET  /jobs/{id}      poll job status
+//	GET  /jobs/{id}/result  fetch counts once the job is done
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /jobs", s.handleSubmit)
+	mux.HandleFunc("GET /jobs/{id}", s.handleStatus)
+	mux.HandleFunc("GET /jobs/{id}/result", s.handleResult)
+	return mux
+}
+
+// ListenAndServe serves the API on addr until the listener fails.
+func (s *Server) ListenAndServe(addr string) error {
+	log.Infof("Serving counting jobs on %s", addr)
+	return http.ListenAndServe(addr, s.Handler())
+}
+
+// handleSubmit accepts a new job. A JSON body names files already
+// visible to the server; any other content type is treated as document
+// text uploaded inline and spooled to a temporary file.
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	var (
+		paths   []string
+		cleanup string
+	)
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		var req jobRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		paths = ExpandArgs(req.Paths, req.Recursive, req.FollowSymlinks)
+		if len(paths) == 0 {
+			http.Error(w, "no files to process", http.StatusBadRequest)
+			return
+		}
+	} else {
+		tmp, err := os.CreateTemp("", "wordcount-upload-*")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if _, err := io.Copy(tmp, r.Body); err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		tmp.Close()
+		paths = []string{tmp.Name()}
+		cleanup = tmp.Name()
+	}
+
+	s.mu.Lock()
+	s.nextID++
+	job := &Job{ID: fmt.Sprintf("job-%d", s.nextID), Status: JobPending, Submitted: time.Now()}
+	s.jobs[job.ID] = job
+	s.mu.Unlock()
+
+	go s.run(job, paths, cleanup)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(job)
+}
+
+// run counts one job in the background and records the outcome.
+func (s *Server) run(job *Job, paths []string, cleanup string) {
+	s.mu.Lock()
+	job.Status = JobRunning
+	s.mu.Unlock()
+
+	counts, err := ProcessFiles(paths, s.opts)
+	if cleanup != "" {
+		os.Remove(cleanup)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if counts == nil {
+		job.Status = JobFailed
+		job.Error = err.Error()
+		return
+	}
+	if err != nil {
+		// Partial success: the counts stand, the skipped files are
+		// noted on the job
+		job.Error = err.Error()
+	}
+	job.Status = JobDone
+	job.Counts = counts
+}
+
+// lookup fetches a job by the {id} path value, writing a 404 when absent.
+func (s *Server) lookup(w http.ResponseWriter, r *http.Request) *Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job := s.jobs[r.PathValue("id")]
+	if job == nil {
+		http.Error(w, "no such job", http.StatusNotFound)
+	}
+	return job
+}
+
+// handleStatus reports a job's current status.
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	job := s.lookup(w, r)
+	if job == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
+// handleResult returns the counts of a finished job, or 409 while it is
+// still running.
+func (s *Server) handleResult(w http.ResponseWriter, r *http.Request) {
+	job := s.lookup(w, r)
+	if job == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch job.Status {
+	case JobDone:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(job.Counts)
+	case JobFailed:
+		http.Error(w, job.Error, http.StatusInternalServerError)
+	default:
+		http.Error(w, "job still "+job.Status, http.StatusConflict)
+	}
+}
@@ -0,0 +1,176 @@
+package wordcount
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "github.com/lib/pq"  // database/sql driver for postgres:// sinks
+	_ "modernc.org/sqlite" // database/sql driver, pure Go so builds stay cgo-free
+)
+
+// WriteSink writes a result to an external sink described as
+// scheme:destination — sqlite:counts.db today; further schemes extend
+// the switch. Sinks receive the rolled-up totals and, when the run was
+// counted with Options.PerFile, the per-file breakdown too, each row
+// tagged with a run ID so many runs can share one destination and be
+// told apart in queries.
+func WriteSink(dest string, res *Result) error {
+	scheme, rest, ok := strings.Cut(dest, ":")
+	if !ok || rest == "" {
+		return fmt.Errorf("sink %q: want scheme:destination (e.g. sqlite:counts.db)", dest)
+	}
+	switch scheme {
+	case "sqlite":
+		return writeSQLite(rest, res)
+	case "postgres", "postgresql":
+		// The whole URL is the connection string
+		return writePostgres(dest, res)
+	case "redis":
+		return writeRedis(strings.TrimPrefix(rest, "//"), res)
+	case "parquet":
+		return writeParquet(rest, res)
+	}
+	return fmt.Errorf("unsupported sink scheme %q", scheme)
+}
+
+// newRunID tags one run's rows. Nanosecond timestamps are unique enough
+// for runs that share a database and sort chronologically as a bonus.
+func newRunID() string {
+	return time.Now().UTC().Format("2006-01-02T15:04:05.999999999Z")
+}
+
+// writeSQLite appends (run_id, file, word, count) rows to the counts
+// table of a SQLite database, creating it on first use. The totals carry
+// an empty file column; per-file rows name their input. One transaction
+// covers the whole run, so readers never see a half-written run.
+func writeSQLite(path string, res *Result) error {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	const schema = `CREATE TABLE IF NOT EXISTS counts (
+		run_id TEXT NOT NULL,
+		file   TEXT NOT NULL,
+		word   TEXT NOT NULL,
+		count  INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS counts_run_word ON counts (run_id, word);`
+	if _, err := db.Exec(schema); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	stmt, err := tx.Prepare("INSERT INTO counts (run_id, file, word, count) VALUES (?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	runID := newRunID()
+	for word, count := range res.Counts {
+		if _, err := stmt.Exec(runID, "", word, count); err != nil {
+			return err
+		}
+	}
+	for _, fr := range res.Files {
+		for word, count := range fr.Counts {
+			if _, err := stmt.Exec(runID, fr.Name, word, count); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// pgBatchSize is how many rows each multi-row upsert carries. Large
+// enough to amortize round trips, small enough to stay under parameter
+// limits (4 parameters per row, Postgres caps a statement at 65535).
+const pgBatchSize = 1000
+
+// writePostgres upserts (run_id, file, word, count) rows into the counts
+// table of a Postgres database reached through the sink URL, creating
+// the table on first use. Rows go in multi-row batches inside one
+// transaction; re-running the same run ID adds onto existing counts
+// instead of duplicating rows, so a retried run stays correct.
+func writePostgres(dsn string, res *Result) error {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	const schema = `CREATE TABLE IF NOT EXISTS counts (
+		run_id TEXT   NOT NULL,
+		file   TEXT   NOT NULL,
+		word   TEXT   NOT NULL,
+		count  BIGINT NOT NULL,
+		PRIMARY KEY (run_id, file, word)
+	)`
+	if _, err := db.Exec(schema); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	runID := newRunID()
+	type row struct {
+		file, word string
+		count      int
+	}
+	batch := make([]row, 0, pgBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		var (
+			sb   strings.Builder
+			args = make([]interface{}, 0, len(batch)*4)
+		)
+		sb.WriteString("INSERT INTO counts (run_id, file, word, count) VALUES ")
+		for i, r := range batch {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			fmt.Fprintf(&sb, "($%d, $%d, $%d, $%d)", i*4+1, i*4+2, i*4+3, i*4+4)
+			args = append(args, runID, r.file, r.word, r.count)
+		}
+		sb.WriteString(" ON CONFLICT (run_id, file, word) DO UPDATE SET count = counts.count + EXCLUDED.count")
+		_, err := tx.Exec(sb.String(), args...)
+		batch = batch[:0]
+		return err
+	}
+	add := func(file, word string, count int) error {
+		batch = append(batch, row{file, word, count})
+		if len(batch) == pgBatchSize {
+			return flush()
+		}
+		return nil
+	}
+
+	for word, count := range res.Counts {
+		if err := add("", word, count); err != nil {
+			return err
+		}
+	}
+	for _, fr := range res.Files {
+		for word, count := range fr.Counts {
+			if err := add(fr.Name, word, count); err != nil {
+				return err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,177 @@
+package wordcount
+
+import (
+	"bufio"
+	"container/heap"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// spillEntryOverhead approximates the per-entry bookkeeping cost of a Go
+// map entry (bucket slot, string header, count) on top of the key bytes.
+// It only needs to be close enough for the -max-mem budget to be honored
+// within a small factor.
+const spillEntryOverhead = 48
+
+// writeSpillRun writes the counts to a temporary file as "word\tcount"
+// lines sorted by word — a sorted run in external-sort terms — and
+// returns the file path. The caller owns (and eventually removes) the file.
+func writeSpillRun(m map[string]int) (string, error) {
+	words := make([]string, 0, len(m))
+	for w := range m {
+		words = append(words, w)
+	}
+	sort.Strings(words)
+
+	tmp, err := os.CreateTemp("", "wordcount-spill-*")
+	if err != nil {
+		return "", err
+	}
+	bw := bufio.NewWriter(tmp)
+	for _, w := range words {
+		if _, err := fmt.Fprintf(bw, "%s\t%d\n", w, m[w]); err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+			return "", err
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+// mergeRun is one sorted run being consumed by the k-way merge.
+type mergeRun struct {
+	scanner *bufio.Scanner
+	path    string
+	word    string
+	count   int
+}
+
+// advance reads the run's next entry, reporting false at end of run.
+func (r *mergeRun) advance() (bool, error) {
+	if !r.scanner.Scan() {
+		return false, r.scanner.Err()
+	}
+	line := r.scanner.Text()
+	tab := strings.LastIndexByte(line, '\t')
+	if tab < 0 {
+		return false, fmt.Errorf("malformed spill line in %s: %q", r.path, line)
+	}
+	count, err := strconv.Atoi(line[tab+1:])
+	if err != nil {
+		return false, fmt.Errorf("malformed spill count in %s: %q", r.path, line)
+	}
+	r.word, r.count = line[:tab], count
+	return true, nil
+}
+
+// runHeap orders merge runs by their current word so the k-way merge can
+// always pop the smallest.
+type runHeap []*mergeRun
+
+func (h runHeap) Len() int            { return len(h) }
+func (h runHeap) Less(i, j int) bool  { return h[i].word < h[j].word }
+func (h runHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *runHeap) Push(x interface{}) { *h = append(*h, x.(*mergeRun)) }
+func (h *runHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// mergeSpillRuns performs an external k-way merge over sorted run files,
+// summing counts for equal words and emitting each aggregated entry in
+// word order. Only one entry per run is held in memory at a time, so the
+// full vocabulary never has to fit in RAM.
+func mergeSpillRuns(paths []string, emit func(word string, count int) error) error {
+	h := make(runHeap, 0, len(paths))
+	var files []*os.File
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		files = append(files, file)
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		run := &mergeRun{scanner: scanner, path: path}
+		ok, err := run.advance()
+		if err != nil {
+			return err
+		}
+		if ok {
+			h = append(h, run)
+		}
+	}
+	heap.Init(&h)
+
+	for h.Len() > 0 {
+		// Pop every run positioned at the smallest word and sum them
+		word := h[0].word
+		count := 0
+		for h.Len() > 0 && h[0].word == word {
+			run := h[0]
+			count += run.count
+			ok, err := run.advance()
+			if err != nil {
+				return err
+			}
+			if ok {
+				heap.Fix(&h, 0)
+			} else {
+				heap.Pop(&h)
+			}
+		}
+		if err := emit(word, count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readSpillRun streams a sorted run back, calling emit for each entry in
+// word order.
+func readSpillRun(path string, emit func(word string, count int) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		tab := strings.LastIndexByte(line, '\t')
+		if tab < 0 {
+			return fmt.Errorf("malformed spill line in %s: %q", path, line)
+		}
+		count, err := strconv.Atoi(line[tab+1:])
+		if err != nil {
+			return fmt.Errorf("malformed spill count in %s: %q", path, line)
+		}
+		if err := emit(line[:tab], count); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
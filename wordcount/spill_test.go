@@ -0,0 +1,123 @@
+package wordcount
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestMergeSpillRuns(t *testing.T) {
+	runs := []map[string]int{
+		{"apple": 1, "banana": 2, "cherry": 3},
+		{"banana": 4, "cherry": 5, "date": 6},
+		{"apple": 7},
+	}
+	var paths []string
+	for _, m := range runs {
+		path, err := writeSpillRun(m)
+		if err != nil {
+			t.Fatalf("writeSpillRun: %v", err)
+		}
+		defer os.Remove(path)
+		paths = append(paths, path)
+	}
+
+	var words []string
+	got := make(map[string]int)
+	err := mergeSpillRuns(paths, func(word string, count int) error {
+		words = append(words, word)
+		got[word] = count
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("mergeSpillRuns: %v", err)
+	}
+
+	want := map[string]int{"apple": 8, "banana": 6, "cherry": 8, "date": 6}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("merged counts = %v, want %v", got, want)
+	}
+	// A word spread across runs must come out once, and in word order
+	if !sort.StringsAreSorted(words) {
+		t.Errorf("merge emitted words out of order: %v", words)
+	}
+	if len(words) != len(want) {
+		t.Errorf("merge emitted %d entries, want %d: %v", len(words), len(want), words)
+	}
+}
+
+// spillCorpus writes a deterministic multi-file corpus with enough
+// vocabulary that a tiny -max-mem budget forces the reducers through
+// many spill runs, and returns the paths with the expected counts.
+func spillCorpus(t *testing.T) ([]string, map[string]int) {
+	t.Helper()
+	dir := t.TempDir()
+	want := make(map[string]int)
+	var paths []string
+	for f := 0; f < 3; f++ {
+		var b strings.Builder
+		// Overlapping vocabularies across files exercise duplicate
+		// keys at run boundaries
+		for i := f * 100; i < f*100+300; i++ {
+			word := base26(i)
+			reps := i%5 + 1
+			for r := 0; r < reps; r++ {
+				b.WriteString(word)
+				b.WriteByte(' ')
+			}
+			want[word] += reps
+		}
+		path := filepath.Join(dir, fmt.Sprintf("spill%d.txt", f))
+		if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, want
+}
+
+func TestProcessFilesSpilledMatchesInMemory(t *testing.T) {
+	files, want := spillCorpus(t)
+	inMem, err := ProcessFiles(files, Options{})
+	if err != nil {
+		t.Fatalf("in-memory run: %v", err)
+	}
+	// A budget this small spills after nearly every partial map
+	spilled, err := ProcessFiles(files, Options{MaxMem: 512})
+	if err != nil {
+		t.Fatalf("spilled run: %v", err)
+	}
+	if !reflect.DeepEqual(inMem, want) {
+		t.Errorf("in-memory counts diverge from expected: got %d words, want %d", len(inMem), len(want))
+	}
+	if !reflect.DeepEqual(spilled, inMem) {
+		t.Errorf("spilled counts diverge from in-memory: got %d words, want %d", len(spilled), len(inMem))
+	}
+}
+
+func TestProcessFilesSortedMatchesInMemory(t *testing.T) {
+	files, want := spillCorpus(t)
+	var words []string
+	got := make(map[string]int)
+	err := ProcessFilesSorted(files, Options{}, func(word string, count int) error {
+		words = append(words, word)
+		if _, dup := got[word]; dup {
+			t.Errorf("word %q emitted twice", word)
+		}
+		got[word] = count
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ProcessFilesSorted: %v", err)
+	}
+	if !sort.StringsAreSorted(words) {
+		t.Errorf("stream emitted words out of order")
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("streamed counts diverge from expected: got %d words, want %d", len(got), len(want))
+	}
+}
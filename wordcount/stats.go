@@ -0,0 +1,314 @@
+package wordcount
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"unicode"
+)
+
+// FileStats holds wc-compatible statistics for one input: lines, words
+// (maximal non-whitespace runs, the wc definition rather than the
+// tokenizer's), bytes, and the longest line in runes.
+type FileStats struct {
+	Name    string
+	Lines   int64
+	Words   int64
+	Bytes   int64
+	MaxLine int64
+}
+
+// add folds another input's statistics into a running total.
+func (s *FileStats) add(o FileStats) {
+	s.Lines += o.Lines
+	s.Words += o.Words
+	s.Bytes += o.Bytes
+	if o.MaxLine > s.MaxLine {
+		s.MaxLine = o.MaxLine
+	}
+}
+
+// StatFiles computes wc-style statistics for every input in parallel
+// with the usual worker pool, returning the per-input stats in input
+// order plus the totals. Inputs that fail are reported the same way the
+// counting pipeline reports them, as InputErrors in the joined error.
+func StatFiles(files []string, opts Options) ([]FileStats, FileStats, error) {
+	opts = opts.withDefaults()
+	var inputs []Input
+	for _, f := range files {
+		inputs = append(inputs, inputsForPath(f, opts)...)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		byName  = make(map[string]FileStats, len(inputs))
+		errList []error
+	)
+	work := make(chan Input)
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for in := range work {
+				rc, err := in.Open()
+				if err != nil {
+					mu.Lock()
+					errList = append(errList, &InputError{Name: in.Name, Err: err})
+					mu.Unlock()
+					continue
+				}
+				s, err := statReader(rc, opts.BufSize)
+				rc.Close()
+				s.Name = in.Name
+				mu.Lock()
+				if err != nil {
+					errList = append(errList, &InputError{Name: in.Name, Err: err})
+				} else {
+					byName[in.Name] = s
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, in := range inputs {
+		work <- in
+	}
+	close(work)
+	wg.Wait()
+
+	var (
+		stats []FileStats
+		total FileStats
+	)
+	total.Name = "total"
+	for _, in := range inputs {
+		if s, ok := byName[in.Name]; ok {
+			stats = append(stats, s)
+			total.add(s)
+		}
+	}
+	return stats, total, errors.Join(errList...)
+}
+
+// statReader tallies one stream. Lines counts newlines like wc, so a
+// final unterminated line still contributes words, bytes, and length
+// but no line.
+func statReader(r io.Reader, bufSize int) (FileStats, error) {
+	br := bufio.NewReaderSize(r, bufSize)
+	var (
+		s       FileStats
+		lineLen int64
+		inWord  bool
+	)
+	for {
+		c, size, err := br.ReadRune()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return s, err
+		}
+		s.Bytes += int64(size)
+		if c == '\n' {
+			s.Lines++
+			if lineLen > s.MaxLine {
+				s.MaxLine = lineLen
+			}
+			lineLen = 0
+			inWord = false
+			continue
+		}
+		lineLen++
+		if unicode.IsSpace(c) {
+			inWord = false
+		} else if !inWord {
+			inWord = true
+			s.Words++
+		}
+	}
+	if lineLen > s.MaxLine {
+		s.MaxLine = lineLen
+	}
+	return s, nil
+}
+
+// WriteLengthReport renders a ModeLengths result: how many tokens had
+// each rune length, with the weighted mean and median. The aggregate
+// map's keys are decimal lengths produced in the mapper.
+func WriteLengthReport(w io.Writer, counts map[string]int) error {
+	type bucket struct{ length, count int }
+	var (
+		buckets []bucket
+		tokens  int64
+		sum     int64
+	)
+	for k, c := range counts {
+		n, err := strconv.Atoi(k)
+		if err != nil {
+			continue
+		}
+		buckets = append(buckets, bucket{length: n, count: c})
+		tokens += int64(c)
+		sum += int64(n) * int64(c)
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].length < buckets[j].length })
+
+	if _, err := fmt.Fprintf(w, "%-10s%s\n%-10s%s\n", "Length", "Tokens", "------", "------"); err != nil {
+		return err
+	}
+	for _, b := range buckets {
+		if _, err := fmt.Fprintf(w, "%-10d%d\n", b.length, b.count); err != nil {
+			return err
+		}
+	}
+	if tokens == 0 {
+		return nil
+	}
+	median := 0
+	for cum, mid := int64(0), (tokens+1)/2; len(buckets) > 0; buckets = buckets[1:] {
+		if cum += int64(buckets[0].count); cum >= mid {
+			median = buckets[0].length
+			break
+		}
+	}
+	_, err := fmt.Fprintf(w, "Tokens: %d  Mean length: %.2f  Median length: %d\n", tokens, float64(sum)/float64(tokens), median)
+	return err
+}
+
+// WriteZipf writes a rank-frequency listing — rank 1 is the most
+// frequent word — ready for log-log plotting in the usual formats.
+// The ranking must already be count-sorted descending, as SortCounts
+// with SortByCount produces.
+func WriteZipf(w io.Writer, ranked []WordCount, format string) error {
+	switch format {
+	case FormatCSV, FormatTSV:
+		sep := ","
+		if format == FormatTSV {
+			sep = "\t"
+		}
+		if _, err := fmt.Fprintf(w, "rank%sfrequency%sword\n", sep, sep); err != nil {
+			return err
+		}
+		for i, wc := range ranked {
+			if _, err := fmt.Fprintf(w, "%d%s%d%s%s\n", i+1, sep, wc.Count, sep, wc.Word); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatTable, "":
+		if _, err := fmt.Fprintf(w, "%-10s%-12s%s\n%-10s%-12s%s\n", "Rank", "Frequency", "Word", "----", "---------", "----"); err != nil {
+			return err
+		}
+		for i, wc := range ranked {
+			if _, err := fmt.Fprintf(w, "%-10d%-12d%s\n", i+1, wc.Count, wc.Word); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// ZipfExponent fits frequency ∝ rank^-s by least squares on the log-log
+// ranking and returns the exponent s with the fit's R² (natural
+// language hovers near s = 1). Fewer than two distinct ranks fit
+// nothing and return zeros.
+func ZipfExponent(ranked []WordCount) (s, r2 float64) {
+	n := float64(len(ranked))
+	if n < 2 {
+		return 0, 0
+	}
+	var sx, sy, sxx, syy, sxy float64
+	for i, wc := range ranked {
+		x := math.Log(float64(i + 1))
+		y := math.Log(float64(wc.Count))
+		sx += x
+		sy += y
+		sxx += x * x
+		syy += y * y
+		sxy += x * y
+	}
+	varX := sxx - sx*sx/n
+	varY := syy - sy*sy/n
+	cov := sxy - sx*sy/n
+	if varX == 0 || varY == 0 {
+		return 0, 0
+	}
+	return -cov / varX, cov * cov / (varX * varY)
+}
+
+// WriteRichness summarizes vocabulary richness from the final count
+// map: hapax and dis legomena (words occurring exactly once and twice),
+// Herdan's C (log vocabulary over log tokens, stable across corpus
+// sizes), and Yule's K (repetitiveness of the frequency spectrum,
+// higher meaning more repetitive). Printed as an optional summary
+// section alongside whatever listing the run produces.
+func WriteRichness(w io.Writer, counts map[string]int) error {
+	var (
+		tokens int64
+		hapax  int
+		dis    int
+		m2     int64 // sum of squared frequencies
+	)
+	for _, c := range counts {
+		tokens += int64(c)
+		m2 += int64(c) * int64(c)
+		switch c {
+		case 1:
+			hapax++
+		case 2:
+			dis++
+		}
+	}
+	types := len(counts)
+	herdan := 0.0
+	if types > 0 && tokens > 1 {
+		herdan = math.Log(float64(types)) / math.Log(float64(tokens))
+	}
+	yule := 0.0
+	if tokens > 0 {
+		yule = 1e4 * float64(m2-tokens) / (float64(tokens) * float64(tokens))
+	}
+	pct := func(n int) float64 {
+		if types == 0 {
+			return 0
+		}
+		return 100 * float64(n) / float64(types)
+	}
+	_, err := fmt.Fprintf(w,
+		"Types: %d  Tokens: %d\nHapax legomena: %d (%.1f%% of types)  Dis legomena: %d (%.1f%%)\nHerdan's C: %.3f  Yule's K: %.1f\n",
+		types, tokens, hapax, pct(hapax), dis, pct(dis), herdan, yule)
+	return err
+}
+
+// WriteVocabStats reports vocabulary statistics per input — distinct
+// words (types), total tokens, and the type/token ratio — so
+// boilerplate-heavy or anomalous files stand out. A low ratio means
+// lots of repetition. The per-file counts must have been retained with
+// Options.PerFile.
+func WriteVocabStats(w io.Writer, files []FileResult) error {
+	if _, err := fmt.Fprintf(w, "%10s %10s %8s  %s\n", "Types", "Tokens", "TTR", "File"); err != nil {
+		return err
+	}
+	for _, fr := range files {
+		tokens := 0
+		for _, c := range fr.Counts {
+			tokens += c
+		}
+		ttr := 0.0
+		if tokens > 0 {
+			ttr = float64(len(fr.Counts)) / float64(tokens)
+		}
+		if _, err := fmt.Fprintf(w, "%10d %10d %8.3f  %s\n", len(fr.Counts), tokens, ttr, fr.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
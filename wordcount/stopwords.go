@@ -0,0 +1,58 @@
+package wordcount
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// englishStopwords is a compact built-in list of very common English words
+// that dominate raw counts without carrying meaning for analysis.
+var englishStopwords = []string{
+	"a", "about", "above", "after", "again", "against", "all", "am", "an",
+	"and", "any", "are", "as", "at", "be", "because", "been", "before",
+	"being", "below", "between", "both", "but", "by", "can", "could", "did",
+	"do", "does", "doing", "down", "during", "each", "few", "for", "from",
+	"further", "had", "has", "have", "having", "he", "her", "here", "hers",
+	"herself", "him", "himself", "his", "how", "i", "if", "in", "into", "is",
+	"it", "its", "itself", "just", "me", "more", "most", "my", "myself",
+	"no", "nor", "not", "now", "of", "off", "on", "once", "only", "or",
+	"other", "our", "ours", "ourselves", "out", "over", "own", "s", "same",
+	"she", "should", "so", "some", "such", "t", "than", "that", "the",
+	"their", "theirs", "them", "themselves", "then", "there", "these",
+	"they", "this", "those", "through", "to", "too", "under", "until", "up",
+	"very", "was", "we", "were", "what", "when", "where", "which", "while",
+	"who", "whom", "why", "will", "with", "would", "you", "your", "yours",
+	"yourself", "yourselves",
+}
+
+// BuiltinStopwords returns the built-in English stop word set.
+func BuiltinStopwords() map[string]struct{} {
+	set := make(map[string]struct{}, len(englishStopwords))
+	for _, w := range englishStopwords {
+		set[w] = struct{}{}
+	}
+	return set
+}
+
+// LoadStopwords reads a stop word set from a file with one word per line.
+// Words are lowercased to match the mapper's normalization, and blank
+// lines and #-prefixed comments are skipped.
+func LoadStopwords(path string) (map[string]struct{}, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	set := make(map[string]struct{})
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		word := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		set[word] = struct{}{}
+	}
+	return set, scanner.Err()
+}
@@ -0,0 +1,66 @@
+package wordcount
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// objectStore abstracts a remote object storage backend: list the keys
+// under a prefix, then stream individual objects. Implementations exist
+// for S3 (s3://), Google Cloud Storage (gs://), and Azure Blob (az://);
+// adding a backend means implementing these two methods and extending
+// the scheme switch in RemoteInputs.
+type objectStore interface {
+	list(prefix string) ([]string, error)
+	get(key string) (io.ReadCloser, error)
+}
+
+// IsRemote reports whether the argument is a remote storage URI.
+func IsRemote(arg string) bool {
+	return strings.HasPrefix(arg, "s3://") ||
+		strings.HasPrefix(arg, "gs://") ||
+		strings.HasPrefix(arg, "az://")
+}
+
+// RemoteInputs lists the objects under a scheme://bucket/prefix URI and
+// returns one Input per object, streamed lazily when a worker dequeues
+// it, so object corpora flow through the pipeline without a download
+// step. A URI naming one object exactly yields that single input.
+func RemoteInputs(uri string) ([]Input, error) {
+	scheme, rest, _ := strings.Cut(uri, "://")
+	bucket, prefix, _ := strings.Cut(rest, "/")
+	if bucket == "" {
+		return nil, fmt.Errorf("%s: missing bucket name", uri)
+	}
+	var (
+		store objectStore
+		err   error
+	)
+	switch scheme {
+	case "s3":
+		store = newS3Client(bucket)
+	case "gs":
+		store = newGCSClient(bucket)
+	case "az":
+		store, err = newAzureClient(bucket)
+	default:
+		err = fmt.Errorf("%s: unsupported storage scheme %q", uri, scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+	keys, err := store.list(prefix)
+	if err != nil {
+		return nil, err
+	}
+	inputs := make([]Input, 0, len(keys))
+	for _, key := range keys {
+		key := key
+		inputs = append(inputs, Input{
+			Name: scheme + "://" + bucket + "/" + key,
+			Open: func() (io.ReadCloser, error) { return store.get(key) },
+		})
+	}
+	return inputs, nil
+}
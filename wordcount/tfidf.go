@@ -0,0 +1,69 @@
+package wordcount
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// TFIDF holds a word's corpus-wide term frequency, the number of documents
+// it appears in, and the resulting TF-IDF score.
+type TFIDF struct {
+	Word    string
+	Count   int     // Total occurrences across the corpus
+	DocFreq int     // Number of input documents containing the word
+	Score   float64 // Count * ln(totalDocs / DocFreq)
+}
+
+// ComputeTFIDF derives TF-IDF scores from a per-file breakdown. Words that
+// appear in every document score zero (they carry no discriminating
+// power), and rarer words are weighted up logarithmically. The result is
+// sorted by score descending, ties broken alphabetically.
+func ComputeTFIDF(files []FileResult) []TFIDF {
+	totalDocs := len(files)
+	if totalDocs == 0 {
+		return nil
+	}
+
+	counts := make(map[string]int)
+	docFreq := make(map[string]int)
+	for _, fr := range files {
+		for w, c := range fr.Counts {
+			counts[w] += c
+			docFreq[w]++
+		}
+	}
+
+	scores := make([]TFIDF, 0, len(counts))
+	for w, c := range counts {
+		df := docFreq[w]
+		scores = append(scores, TFIDF{
+			Word:    w,
+			Count:   c,
+			DocFreq: df,
+			Score:   float64(c) * math.Log(float64(totalDocs)/float64(df)),
+		})
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		return scores[i].Word < scores[j].Word
+	})
+	return scores
+}
+
+// WriteTFIDF writes TF-IDF scores to w in the same tabular style as the
+// count output.
+func WriteTFIDF(w io.Writer, scores []TFIDF) error {
+	if _, err := fmt.Fprintf(w, "%-12s%-8s%-10s%s\n", "TF-IDF", "Docs", "Count", "Word"); err != nil {
+		return err
+	}
+	for _, s := range scores {
+		if _, err := fmt.Fprintf(w, "%-12.3f%-8d%-10d%s\n", s.Score, s.DocFreq, s.Count, s.Word); err != nil {
+			return err
+		}
+	}
+	return nil
+}
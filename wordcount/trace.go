@@ -0,0 +1,12 @@
+package wordcount
+
+import (
+	"go.opentelemetry.io/otel"
+)
+
+// tracer instruments the pipeline phases (one span per run, per mapped
+// input, and per reducer). It resolves against the global
+// TracerProvider, so tracing is a no-op unless the embedding process
+// installs a provider — the CLI does so with -otel-trace — and the
+// spans then appear alongside whatever traces the caller already emits.
+var tracer = otel.Tracer("word_counter/wordcount")
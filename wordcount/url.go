@@ -0,0 +1,71 @@
+package wordcount
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// IsURL reports whether the argument is an http:// or https:// URL to
+// be streamed through the pipeline.
+func IsURL(arg string) bool {
+	return strings.HasPrefix(arg, "http://") || strings.HasPrefix(arg, "https://")
+}
+
+// urlInput returns an Input that GETs the URL when a worker dequeues it
+// and streams the response body through the tokenizer, honoring
+// Options.FetchTimeout and Options.FetchLimit.
+func urlInput(rawURL string, opts Options) Input {
+	return Input{
+		Name: rawURL,
+		Open: func() (io.ReadCloser, error) { return openURL(rawURL, opts) },
+	}
+}
+
+// openURL fetches the URL, failing on non-200 responses and on bodies
+// that exceed the fetch limit.
+func openURL(rawURL string, opts Options) (io.ReadCloser, error) {
+	client := &http.Client{Timeout: opts.FetchTimeout}
+	resp, err := client.Get(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: %s", rawURL, resp.Status)
+	}
+	if opts.FetchLimit > 0 {
+		if resp.ContentLength > opts.FetchLimit {
+			resp.Body.Close()
+			return nil, fmt.Errorf("%s: response is %d bytes, over the %d byte fetch limit", rawURL, resp.ContentLength, opts.FetchLimit)
+		}
+		// Chunked responses carry no length up front, so the limit is
+		// also enforced while reading
+		return &limitedBody{body: resp.Body, remaining: opts.FetchLimit, name: rawURL}, nil
+	}
+	return resp.Body, nil
+}
+
+// limitedBody reads a response body and fails once it exceeds the fetch
+// limit, so an unexpectedly large document is skipped rather than
+// silently truncated.
+type limitedBody struct {
+	body      io.ReadCloser
+	remaining int64
+	name      string
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1] // read one byte past the limit to detect overrun
+	}
+	n, err := l.body.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n, fmt.Errorf("%s: response exceeds the fetch limit", l.name)
+	}
+	return n, err
+}
+
+func (l *limitedBody) Close() error { return l.body.Close() }
@@ -0,0 +1,101 @@
+package wordcount
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// Policies for bytes that are not valid UTF-8 after decoding.
+const (
+	InvalidReplace = "replace" // substitute U+FFFD (default)
+	InvalidSkip    = "skip"    // drop the bytes
+	InvalidError   = "error"   // fail the input
+)
+
+// newUTF8Sanitizer wraps r so the tokenizer only ever sees valid UTF-8:
+// a leading BOM is stripped and invalid sequences are handled per mode
+// instead of silently becoming mojibake tokens. The detected encodings
+// always decode cleanly, so this matters for forced -encoding utf-8 and
+// for files whose corruption starts beyond the detection window.
+func newUTF8Sanitizer(r io.Reader, name, mode string) (io.Reader, error) {
+	switch mode {
+	case "":
+		mode = InvalidReplace
+	case InvalidReplace, InvalidSkip, InvalidError:
+	default:
+		return nil, fmt.Errorf("invalid UTF-8 policy %q: want replace, skip, or error", mode)
+	}
+	return &utf8Sanitizer{r: r, name: name, mode: mode}, nil
+}
+
+// utf8Sanitizer validates a byte stream as UTF-8, buffering runes split
+// across read boundaries so they are never misjudged.
+type utf8Sanitizer struct {
+	r       io.Reader
+	name    string
+	mode    string
+	in      []byte
+	pending []byte
+	offset  int64 // bytes consumed, for error positions
+	started bool
+	err     error
+}
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+func (s *utf8Sanitizer) Read(p []byte) (int, error) {
+	for len(s.pending) == 0 {
+		if s.err != nil && len(s.in) == 0 {
+			return 0, s.err
+		}
+		if s.err == nil {
+			chunk := getBuf(32 * 1024)
+			n, err := s.r.Read(chunk)
+			s.in = append(s.in, chunk[:n]...)
+			putBuf(chunk)
+			s.err = err
+		}
+		if !s.started && len(s.in) >= len(utf8BOM) {
+			s.in = bytes.TrimPrefix(s.in, utf8BOM)
+			s.started = true
+		}
+		if err := s.sanitize(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+// sanitize moves complete runes from in to pending, applying the policy
+// to invalid bytes and keeping a truncated trailing rune for the next
+// read (unless the stream is done, when nothing more can complete it).
+func (s *utf8Sanitizer) sanitize() error {
+	data := s.in
+	atEOF := s.err != nil
+	for len(data) > 0 {
+		r, size := utf8.DecodeRune(data)
+		if r == utf8.RuneError && size == 1 {
+			if !utf8.FullRune(data) && !atEOF {
+				break // wait for the rest of the rune
+			}
+			switch s.mode {
+			case InvalidReplace:
+				s.pending = utf8.AppendRune(s.pending, utf8.RuneError)
+			case InvalidError:
+				return fmt.Errorf("%s: invalid UTF-8 at byte %d", s.name, s.offset)
+			}
+			data = data[1:]
+			s.offset++
+			continue
+		}
+		s.pending = append(s.pending, data[:size]...)
+		data = data[size:]
+		s.offset += int64(size)
+	}
+	s.in = append(s.in[:0], data...)
+	return nil
+}
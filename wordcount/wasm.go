@@ -0,0 +1,118 @@
+package wordcount
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+// wasmMapper adapts a WebAssembly module to the Mapper interface. The
+// guest ABI is deliberately small:
+//
+//	alloc(size u32) -> u32          allocate size bytes, return the offset
+//	tokenize(ptr, len u32) -> u64   tokenize the bytes at ptr; the result
+//	                                packs the output offset in the high 32
+//	                                bits and its length in the low 32, the
+//	                                output being tokens joined by '\n'
+//
+// Each emitted token is counted once. The module is compiled once and
+// instantiated per input, since WebAssembly instances are single-threaded
+// while the worker pool is not.
+type wasmMapper struct {
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+}
+
+// LoadWASMMapper compiles a WebAssembly tokenizer module and returns it
+// as a Mapper. Unlike Go plugins, WASM modules are portable across
+// platforms and toolchains and run sandboxed: the guest sees only the
+// bytes it is handed, never the filesystem. WASI imports are provided
+// for guests built with wasip1 toolchains (Go, TinyGo, Rust).
+func LoadWASMMapper(path string) (Mapper, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	r := wazero.NewRuntime(ctx)
+	wasi_snapshot_preview1.MustInstantiate(ctx, r)
+	compiled, err := r.CompileModule(ctx, src)
+	if err != nil {
+		r.Close(ctx)
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	exports := compiled.ExportedFunctions()
+	for _, name := range []string{"alloc", "tokenize"} {
+		if _, ok := exports[name]; !ok {
+			r.Close(ctx)
+			return nil, fmt.Errorf("%s does not export %q (want alloc and tokenize)", path, name)
+		}
+	}
+	return &wasmMapper{runtime: r, compiled: compiled}, nil
+}
+
+// Map instantiates the module and feeds it the input line by line,
+// emitting each token the guest returns.
+func (m *wasmMapper) Map(name string, rd io.Reader, emit func(word string, count int)) error {
+	ctx := context.Background()
+	// Anonymous instances so concurrent workers never collide on the
+	// module name
+	mod, err := m.runtime.InstantiateModule(ctx, m.compiled, wazero.NewModuleConfig().WithName(""))
+	if err != nil {
+		return err
+	}
+	defer mod.Close(ctx)
+	// Reactor-style modules (Go and TinyGo wasip1 builds) initialize
+	// through _initialize rather than _start
+	if init := mod.ExportedFunction("_initialize"); init != nil {
+		if _, err := init.Call(ctx); err != nil {
+			return err
+		}
+	}
+	alloc := mod.ExportedFunction("alloc")
+	tokenize := mod.ExportedFunction("tokenize")
+	mem := mod.Memory()
+
+	scanner := bufio.NewScanner(rd)
+	buf := getBuf(DefaultBufSize)
+	defer putBuf(buf)
+	scanner.Buffer(buf, DefaultBufSize)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		ret, err := alloc.Call(ctx, uint64(len(line)))
+		if err != nil {
+			return err
+		}
+		ptr := uint32(ret[0])
+		if !mem.Write(ptr, line) {
+			return fmt.Errorf("guest alloc returned offset %d outside its memory", ptr)
+		}
+		ret, err = tokenize.Call(ctx, uint64(ptr), uint64(len(line)))
+		if err != nil {
+			return err
+		}
+		outPtr, outLen := uint32(ret[0]>>32), uint32(ret[0])
+		if outLen == 0 {
+			continue
+		}
+		buf, ok := mem.Read(outPtr, outLen)
+		if !ok {
+			return fmt.Errorf("guest tokenize returned range %d+%d outside its memory", outPtr, outLen)
+		}
+		for _, tok := range strings.Split(string(buf), "\n") {
+			if tok != "" {
+				emit(tok, 1)
+			}
+		}
+	}
+	return scanner.Err()
+}
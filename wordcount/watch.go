@@ -0,0 +1,109 @@
+package wordcount
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+	log "github.com/sirupsen/logrus"
+)
+
+// Watch counts the files in dir and then keeps the aggregate up to date
+// as files appear, change, or disappear: a modified file's previous
+// contribution is subtracted before its fresh counts are added, so the
+// totals never need a full recount. onUpdate runs after the initial scan
+// and after every incremental update with the current aggregate. Watch
+// returns when Options.Stop fires or the watcher fails. Dotfiles (editor
+// temp files, swap files) are ignored.
+func Watch(dir string, opts Options, onUpdate func(total map[string]int)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	if err := watcher.Add(dir); err != nil {
+		return err
+	}
+
+	// perFile remembers each file's last counted contribution so it can
+	// be subtracted when the file changes or goes away
+	perFile := make(map[string]map[string]int)
+	total := make(map[string]int)
+
+	subtract := func(path string) {
+		for w, c := range perFile[path] {
+			total[w] -= c
+			if total[w] <= 0 {
+				delete(total, w)
+			}
+		}
+		delete(perFile, path)
+	}
+	recount := func(path string) {
+		subtract(path)
+		counts, err := ProcessFiles([]string{path}, opts)
+		if err != nil {
+			log.Warnf("Failed to recount %s: %v", path, err)
+		}
+		if len(counts) == 0 {
+			return
+		}
+		perFile[path] = counts
+		for w, c := range counts {
+			total[w] += c
+		}
+	}
+	watchable := func(path string) bool {
+		if strings.HasPrefix(filepath.Base(path), ".") {
+			return false
+		}
+		info, err := os.Stat(path)
+		return err == nil && info.Mode().IsRegular()
+	}
+
+	// Count what is already there before reacting to changes
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		if watchable(path) {
+			recount(path)
+		}
+	}
+	onUpdate(total)
+
+	for {
+		select {
+		case <-opts.Stop:
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			switch {
+			case event.Has(fsnotify.Create) || event.Has(fsnotify.Write):
+				if !watchable(event.Name) {
+					continue
+				}
+				recount(event.Name)
+			case event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename):
+				if _, tracked := perFile[event.Name]; !tracked {
+					continue
+				}
+				subtract(event.Name)
+			default:
+				continue
+			}
+			log.Infof("Recounted after %s on %s", event.Op, event.Name)
+			onUpdate(total)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return err
+		}
+	}
+}
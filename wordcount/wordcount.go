@@ -0,0 +1,1418 @@
+// Package wordcount implements a parallel MapReduce-style word counting
+// pipeline. Inputs are distributed across a pool of worker goroutines that
+// tokenize their contents into per-input partial counts, and a pool of
+// reducer goroutines aggregates the partials into a single result map.
+package wordcount
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/clipperhouse/uax29/words"
+	"github.com/kljensen/snowball"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// DefaultBufSize is the read buffer size used when Options.BufSize is zero.
+const DefaultBufSize = 1024 * 1024 // 1 MB
+
+// Segmenter modes for Options.Segmenter.
+const (
+	// SegmenterLetters is the historical tokenizer: maximal runs of
+	// Unicode letters, everything else a delimiter.
+	SegmenterLetters = "letters"
+	// SegmenterUAX29 segments words per Unicode UAX #29 boundary rules,
+	// which handles contractions, numbers, and non-Latin scripts properly.
+	SegmenterUAX29 = "uax29"
+	// SegmenterCJK tokenizes runs of CJK ideographs into overlapping
+	// character bigrams (a standard dictionary-free fallback for Chinese
+	// and Japanese, which have no word-delimiting spaces) while other
+	// scripts keep the letter-run rules.
+	SegmenterCJK = "cjk"
+)
+
+// Counting modes for Options.Mode.
+const (
+	// ModeWords counts tokenized words — the default.
+	ModeWords = "words"
+	// ModeChars counts Unicode code point frequencies instead, useful
+	// for encoding audits and keyboard layout research. Whitespace is
+	// skipped; the other filters and outputs apply unchanged.
+	ModeChars = "chars"
+	// ModeWC reports wc-compatible statistics — lines, words, bytes,
+	// and max line length per file plus totals — instead of counting
+	// word frequencies. See StatFiles.
+	ModeWC = "wc"
+	// ModeLengths histograms token lengths instead of counting the
+	// tokens themselves, with mean and median in the report — a quick
+	// corpus-quality check. See WriteLengthReport.
+	ModeLengths = "lengths"
+)
+
+// Hyphen policies for Options.Hyphens.
+const (
+	// HyphenSplit is the historical behavior: hyphens delimit tokens,
+	// so "state-of-the-art" counts as four words.
+	HyphenSplit = "split"
+	// HyphenKeep treats hyphenated compounds as single tokens.
+	HyphenKeep = "keep"
+	// HyphenBoth counts the compound and each of its parts.
+	HyphenBoth = "both"
+)
+
+// Options configures the counting pipeline. The zero value picks sensible
+// defaults: one worker per CPU and a 1 MB read buffer.
+type Options struct {
+	Workers   int                 // Number of worker and reducer goroutines
+	BufSize   int                 // Read buffer size in bytes for each worker
+	Stopwords map[string]struct{} // Words dropped in the mapper after normalization
+	MinLen    int                 // Drop tokens shorter than this many runes (0 = no minimum)
+	MaxLen    int                 // Drop tokens longer than this many runes (0 = no maximum)
+
+	// CaseSensitive disables the default lowercase normalization so
+	// "Apple" and "apple" are counted separately.
+	CaseSensitive bool
+
+	// Locale selects language-aware case folding for the lowercase
+	// normalization (a BCP 47 tag like "tr" or "az"), fixing languages
+	// where strings.ToLower is wrong — Turkish dotted versus dotless I
+	// being the classic. Empty keeps the fast Unicode default.
+	Locale string
+
+	// Mode selects what gets counted: ModeWords (default) tokenizes
+	// words, ModeChars counts individual code points.
+	Mode string
+
+	// Segmenter selects the tokenization rules: SegmenterLetters
+	// (default), SegmenterUAX29, or SegmenterCJK.
+	Segmenter string
+
+	// Stem applies Snowball stemming in the mapper when set to a
+	// language code ("en", "es", "fr", ...), so inflected forms of a
+	// word aggregate into one entry.
+	Stem string
+
+	// Lemmas maps inflected forms to their dictionary lemma ("went" to
+	// "go", "mice" to "mouse"), applied in the mapper before stemming.
+	// Unlisted words pass through. See BuiltinLemmas and LoadLemmas.
+	Lemmas map[string]string
+
+	// MaxMem bounds (approximately) the total bytes of in-memory count
+	// state across all reducers. When a reducer's share is exceeded it
+	// spills a sorted run of its counts to a temporary file and starts a
+	// fresh map, so vocabularies larger than RAM can be processed.
+	MaxMem int64
+
+	// NGram counts sequences of N consecutive tokens (joined with a
+	// single space) instead of single words when N > 1. Sequences never
+	// cross input boundaries.
+	NGram int
+
+	// CoWindow counts unordered word pairs appearing within this many
+	// tokens of each other instead of single words (0 = off). Pair keys
+	// are the two words in alphabetical order joined by a space, a
+	// sparse co-occurrence output for embedding and graph analysis.
+	CoWindow int
+
+	// ChunkSize, when positive, splits files larger than two chunks into
+	// word-aligned byte ranges processed by multiple workers in
+	// parallel, so one huge file no longer pins a single worker.
+	ChunkSize int64
+
+	// UseMmap reads plain files through a read-only memory mapping
+	// instead of buffered reads, avoiding a userspace copy on large
+	// files. Small files automatically fall back to normal reads.
+	UseMmap bool
+
+	// PerFile retains each input's counts in Result.Files in addition to
+	// the rolled-up aggregate.
+	PerFile bool
+
+	// IncludeDigits treats digits as word characters in the default
+	// letters segmenter, so tokens like "2024" and "sha256" survive.
+	IncludeDigits bool
+
+	// KeepApostrophes treats apostrophes (straight or typographic)
+	// inside the default letters segmenter's word runs as word
+	// characters, so "don't" and "o'clock" survive as single tokens.
+	// Leading and trailing apostrophes are still trimmed, keeping
+	// quoted words and bare possessive markers out of the counts.
+	KeepApostrophes bool
+
+	// ExpandContractions replaces common English contractions with
+	// their expanded words after case folding, so "don't" counts as
+	// "do" and "not". Implies KeepApostrophes; unlisted apostrophe
+	// words (possessives, "o'clock") pass through intact.
+	ExpandContractions bool
+
+	// Hyphens controls hyphenated compounds in the default letters
+	// segmenter: HyphenSplit (default) explodes "state-of-the-art"
+	// into its parts, HyphenKeep counts it as one token, and
+	// HyphenBoth counts the compound and each part. Unicode hyphens
+	// (U+2010, U+2011) are treated the same as ASCII "-".
+	Hyphens string
+
+	// FoldDiacritics strips combining marks from inputs (after Unicode
+	// decomposition) so accented and plain spellings — "café" and
+	// "cafe" — count as the same word.
+	FoldDiacritics bool
+
+	// Mapper, when set, replaces the built-in tokenizer: each prepared
+	// input stream is handed to it instead of countWords. Typically
+	// loaded from a Go plugin; see LoadPlugin.
+	Mapper Mapper
+
+	// Reducer, when set, replaces plain summation when partial maps
+	// are folded into a partition's aggregate. See LoadPlugin.
+	Reducer Reducer
+
+	// CombinerFlush, when positive, flushes a worker's partial counts
+	// to the reducers whenever they reach this many distinct entries,
+	// instead of once per entire input — smoothing worker memory usage
+	// and pipelining reduce work on huge files. Ignored with PerFile,
+	// which needs the complete per-input map.
+	CombinerFlush int
+
+	// CombinerFlushBytes is the combiner threshold expressed in
+	// approximate bytes of count state instead of entries; either
+	// limit triggers a flush.
+	CombinerFlushBytes int64
+
+	// MaxReadRate caps the pipeline's aggregate read throughput in
+	// bytes per second (0 = unlimited), shared across all workers, so
+	// counting a corpus on production storage doesn't saturate the
+	// backend. Compressed inputs are charged at their on-disk rate.
+	MaxReadRate int64
+
+	// ReadWorkers, when positive, splits each worker's job in two: this
+	// many reader goroutines do the file I/O — sized for the storage
+	// device — while the Workers tokenizer goroutines stay sized for
+	// the CPUs, connected by bounded chunk channels. Zero keeps the
+	// historical single-stage workers that read and tokenize together.
+	ReadWorkers int
+
+	// StdlibMap counts with the built-in Go map instead of the
+	// specialized open-addressing table, for comparison.
+	StdlibMap bool
+
+	// Sketch, when set, counts into a shared Count-Min Sketch instead
+	// of exact maps: memory stays fixed no matter how large the
+	// vocabulary grows, and the caller reads estimates, heavy hitters,
+	// and error bounds off the sketch after the run. Result maps come
+	// back empty in this mode.
+	Sketch *CountMinSketch
+
+	// Distinct, when set, only estimates distinct-word cardinality:
+	// each input feeds a per-file HyperLogLog that is merged into the
+	// counter's total, and no count maps are built. Result maps come
+	// back empty in this mode too.
+	Distinct *DistinctCounter
+
+	// TokenRegex overrides the segmenter entirely: every match of the
+	// pattern becomes a token, letting callers define what a word is
+	// (apostrophes, hashtags, identifiers with digits, ...).
+	TokenRegex *regexp.Regexp
+
+	// CountRegex counts raw matches of an arbitrary pattern (IP
+	// addresses, error codes, ...) instead of tokenizing words. Unlike
+	// TokenRegex, matches skip case folding and the token filters, so
+	// the result keys are exactly what the pattern matched.
+	CountRegex *regexp.Regexp
+
+	// Progress renders a live progress line (files done, bytes read,
+	// throughput, ETA) on stderr while the pipeline runs.
+	Progress bool
+
+	// Stop, when closed, makes the pipeline stop enqueuing new inputs
+	// and drain the ones already in flight. Inputs never handed to a
+	// worker are reported in Result.Unprocessed, so an interrupted run
+	// still yields a usable partial aggregate.
+	Stop <-chan struct{}
+
+	// Extract runs each input through a text extractor before
+	// tokenization: ExtractHTML strips tags, scripts, and styles so
+	// scraped pages count their visible text instead of markup.
+	Extract string
+
+	// MarkdownCode counts fenced code blocks and inline code spans in
+	// ExtractMarkdown mode instead of dropping them.
+	MarkdownCode bool
+
+	// CSVColumn parses inputs as CSV and tokenizes only the listed
+	// columns (comma-separated header names and/or 1-based indexes).
+	CSVColumn string
+
+	// JSONField parses inputs as JSON (one value per line or
+	// concatenated) and tokenizes only the string field at this dot
+	// path, e.g. ".message" or "event.text".
+	JSONField string
+
+	// Encoding forces inputs to be decoded from a named character
+	// encoding (any WHATWG label, e.g. "utf-16le", "latin1",
+	// "shift_jis"). Empty means detect: UTF-16 by BOM or byte pattern,
+	// valid UTF-8 as-is, Windows-1252 otherwise.
+	Encoding string
+
+	// InvalidUTF8 picks what happens to bytes that are still not valid
+	// UTF-8 after decoding: InvalidReplace substitutes U+FFFD (the
+	// default), InvalidSkip drops them, InvalidError fails the input.
+	InvalidUTF8 string
+
+	// Normalize applies Unicode normalization ("nfc" or "nfkc") after
+	// decoding, so canonically-equivalent spellings count together.
+	Normalize string
+
+	// FetchTimeout bounds each HTTP(S) URL input's whole request,
+	// connection included (0 = no limit; the run-level deadline still
+	// applies).
+	FetchTimeout time.Duration
+
+	// FetchLimit fails any HTTP(S) URL input whose body exceeds this
+	// many bytes (0 = no limit). Failing beats silently truncating,
+	// which would miscount without anyone noticing.
+	FetchLimit int64
+
+	// MaxFileSize skips local files larger than this many bytes
+	// (0 = no limit), protecting batch runs from a stray huge file.
+	MaxFileSize int64
+
+	// TruncateLarge reads the first MaxFileSize bytes of oversized
+	// files instead of skipping them.
+	TruncateLarge bool
+}
+
+// withDefaults fills in defaults for unset options.
+func (o Options) withDefaults() Options {
+	if o.Workers <= 0 {
+		o.Workers = runtime.NumCPU()
+	}
+	if o.BufSize <= 0 {
+		o.BufSize = DefaultBufSize
+	}
+	return o
+}
+
+// Input is a single named source of text for the pipeline. Open is called
+// by a worker when the input is dequeued, so inputs are only held open
+// while they are being processed.
+type Input struct {
+	Name string
+	Open func() (io.ReadCloser, error)
+}
+
+// FileInput returns an Input backed by the named file. The name "-" is
+// treated as standard input so the tool can be used in shell pipelines.
+func FileInput(name string) Input {
+	if name == "-" {
+		return StdinInput()
+	}
+	return Input{
+		Name: name,
+		Open: func() (io.ReadCloser, error) { return os.Open(name) },
+	}
+}
+
+// truncatedInput returns an Input reading only the first limit bytes of
+// a file, for oversized files kept by Options.TruncateLarge.
+func truncatedInput(name string, limit int64) Input {
+	return Input{
+		Name: name,
+		Open: func() (io.ReadCloser, error) {
+			f, err := os.Open(name)
+			if err != nil {
+				return nil, err
+			}
+			return &memberReadCloser{Reader: io.LimitReader(f, limit), closers: []io.Closer{f}}, nil
+		},
+	}
+}
+
+// StdinInput returns an Input that reads from standard input. Closing the
+// input is a no-op so the process's stdin stays usable.
+func StdinInput() Input {
+	return Input{
+		Name: "stdin",
+		Open: func() (io.ReadCloser, error) { return io.NopCloser(os.Stdin), nil },
+	}
+}
+
+// FileResult holds the word counts contributed by a single input.
+type FileResult struct {
+	Name   string
+	Counts map[string]int
+}
+
+// Result is the outcome of a pipeline run: the aggregate counts, plus a
+// per-input breakdown when Options.PerFile was set.
+type Result struct {
+	Counts map[string]int // Aggregate counts across all inputs
+	Files  []FileResult   // Per-input breakdown (only with Options.PerFile)
+
+	// Unprocessed names the inputs that were never handed to a worker
+	// because Options.Stop fired first; the counts above cover
+	// everything else.
+	Unprocessed []string
+}
+
+// ProcessFiles processes the list of files and returns the final word count
+// result. A file named "-" is read from standard input, and archive files
+// (.zip, .tar, .tar.gz, .tgz) contribute one input per member. When some
+// files fail to open, the counts over the readable files are returned
+// together with the per-file errors.
+func ProcessFiles(files []string, opts Options) (map[string]int, error) {
+	res, err := ProcessFilesDetailed(files, opts)
+	if res == nil {
+		return nil, err
+	}
+	return res.Counts, err
+}
+
+// ProcessFilesDetailed is like ProcessFiles but returns the full Result,
+// including the per-input breakdown when Options.PerFile is set.
+func ProcessFilesDetailed(files []string, opts Options) (*Result, error) {
+	return ProcessFilesContext(context.Background(), files, opts)
+}
+
+// ProcessFilesContext is ProcessFilesDetailed with cancellation: when ctx
+// is cancelled or times out, the pipeline stops enqueuing inputs,
+// interrupts in-progress reads at the next read call, and returns the
+// partial Result alongside ctx's error, with the abandoned inputs listed
+// in Result.Unprocessed.
+func ProcessFilesContext(ctx context.Context, files []string, opts Options) (*Result, error) {
+	inputs := make([]Input, 0, len(files))
+	for _, fn := range files {
+		inputs = append(inputs, inputsForPath(fn, opts)...)
+	}
+	return ProcessInputsContext(ctx, inputs, opts)
+}
+
+// ProcessInputs processes arbitrary inputs and returns the final word count result.
+func ProcessInputs(inputs []Input, opts Options) (map[string]int, error) {
+	res, err := ProcessInputsDetailed(inputs, opts)
+	if res == nil {
+		return nil, err
+	}
+	return res.Counts, err
+}
+
+// ProcessFilesSorted processes files like ProcessFiles but streams the
+// final aggregated counts to emit in ascending word order, without ever
+// materializing the full vocabulary in memory: every reducer spills its
+// state to sorted run files and the runs are combined with an external
+// k-way merge.
+func ProcessFilesSorted(files []string, opts Options, emit func(word string, count int) error) error {
+	inputs := make([]Input, 0, len(files))
+	for _, fn := range files {
+		inputs = append(inputs, inputsForPath(fn, opts)...)
+	}
+	return ProcessInputsSorted(inputs, opts, emit)
+}
+
+// ProcessInputsSorted is the Input-level variant of ProcessFilesSorted.
+func ProcessInputsSorted(inputs []Input, opts Options, emit func(word string, count int) error) error {
+	opts = opts.withDefaults()
+	// Per-input failures do not invalidate the runs that were written;
+	// merge what succeeded and report the failures alongside
+	runs, _, perr := runPipeline(context.Background(), inputs, opts, true)
+	var all []string
+	for _, rs := range runs {
+		all = append(all, rs...)
+	}
+	defer func() {
+		for _, path := range all {
+			os.Remove(path)
+		}
+	}()
+	if err := mergeSpillRuns(all, emit); err != nil {
+		return err
+	}
+	return perr
+}
+
+// ProcessInputsDetailed processes arbitrary inputs and returns the full Result.
+//
+// The reduce phase is a hash-partitioned shuffle, as in a real MapReduce:
+// each worker splits its per-input counts into one partition per reducer
+// (keyed by word hash) and sends partition i to reducer i's own channel.
+// Every reducer therefore owns a disjoint key range and aggregates into a
+// private map with no locking anywhere on the hot path.
+func ProcessInputsDetailed(inputs []Input, opts Options) (*Result, error) {
+	return ProcessInputsContext(context.Background(), inputs, opts)
+}
+
+// ProcessInputsContext is the Input-level, cancellable variant of
+// ProcessInputsDetailed. On cancellation the partial Result is returned
+// together with ctx's error. Inputs that failed to open or decompress are
+// reported as InputError values joined into the returned error (and
+// extractable with InputErrors); the Result still covers every input
+// that succeeded.
+func ProcessInputsContext(ctx context.Context, inputs []Input, opts Options) (*Result, error) {
+	opts = opts.withDefaults()
+	_, finalResult, err := runPipeline(ctx, inputs, opts, false)
+	return finalResult, errors.Join(err, ctx.Err())
+}
+
+// runPipeline executes the map and shuffle/reduce phases. With spillFinal
+// set, every reducer spills its state to sorted run files and the
+// per-reducer run paths are returned; otherwise the reducers' maps are
+// combined into the returned Result.
+func runPipeline(ctx context.Context, inputs []Input, opts Options, spillFinal bool) ([][]string, *Result, error) {
+	maxWorkers := opts.Workers
+	ctx, pipeSpan := tracer.Start(ctx, "wordcount.pipeline",
+		trace.WithAttributes(attribute.Int("inputs", len(inputs)), attribute.Int("workers", maxWorkers)))
+	defer pipeSpan.End()
+	workersWG := new(sync.WaitGroup)
+	workQueue := make(chan Input, maxWorkers)
+	reducerWG := new(sync.WaitGroup)
+	finalResult := &Result{}
+
+	// One channel per reducer; partition i always goes to reducer i
+	reduceChans := make([]chan map[string]int, maxWorkers)
+	for i := range reduceChans {
+		reduceChans[i] = make(chan map[string]int, 1)
+	}
+	reducerMaps := make([]map[string]int, maxWorkers)
+	reducerRuns := make([][]string, maxWorkers)
+
+	// Per-input results and failures are collected off to the side
+	fileResults := make(chan FileResult, maxWorkers)
+	inputErrs := make(chan error, maxWorkers)
+	collectorDone := make(chan struct{})
+	var failures []error
+	go func() {
+		defer close(collectorDone)
+		frs, errs := fileResults, inputErrs
+		for frs != nil || errs != nil {
+			select {
+			case fr, ok := <-frs:
+				if !ok {
+					frs = nil
+					continue
+				}
+				finalResult.Files = append(finalResult.Files, fr)
+			case e, ok := <-errs:
+				if !ok {
+					errs = nil
+					continue
+				}
+				failures = append(failures, e)
+			}
+		}
+	}()
+
+	// Start the reducer goroutines, each owning one key partition
+	for i := 0; i < maxWorkers; i++ {
+		reducer(ctx, reducerWG, reducerMaps, reducerRuns, reduceChans[i], i, opts.MaxMem/int64(maxWorkers), spillFinal, opts.Reducer)
+	}
+	// Live progress is rendered off to the side; workers feed it
+	// through atomic counters
+	var prog *progressTracker
+	if opts.Progress {
+		prog = startProgress(len(inputs))
+	}
+
+	// Start worker goroutines to process inputs; the rate limiter is
+	// shared so -max-read-rate bounds the pipeline, not each worker
+	var limiter *rateLimiter
+	if opts.MaxReadRate > 0 {
+		limiter = newRateLimiter(opts.MaxReadRate)
+	}
+	// With a reader pool the tokenizer workers consume staged in-memory
+	// streams instead of opening files themselves
+	tokenQueue := (<-chan Input)(workQueue)
+	if opts.ReadWorkers > 0 {
+		tokenQueue = readerPool(ctx, workQueue, opts.ReadWorkers, opts.BufSize)
+	}
+	for i := 0; i < maxWorkers; i++ {
+		processInput(ctx, workersWG, reduceChans, fileResults, inputErrs, tokenQueue, opts, prog, limiter)
+	}
+
+	// Enqueue all inputs into the work queue; a Stop signal or context
+	// cancellation abandons the remainder so in-flight files can drain
+	// and partial results survive
+enqueue:
+	for i, in := range inputs {
+		select {
+		case workQueue <- in:
+			metrics.queueDepth.Add(1)
+		case <-opts.Stop:
+			for _, rest := range inputs[i:] {
+				finalResult.Unprocessed = append(finalResult.Unprocessed, rest.Name)
+			}
+			break enqueue
+		case <-ctx.Done():
+			for _, rest := range inputs[i:] {
+				finalResult.Unprocessed = append(finalResult.Unprocessed, rest.Name)
+			}
+			break enqueue
+		}
+	}
+	close(workQueue)
+	workersWG.Wait() // Wait for all workers to complete their tasks
+	for _, ch := range reduceChans {
+		close(ch) // Signal that no more partitions are coming
+	}
+	close(fileResults)
+	close(inputErrs)
+	reducerWG.Wait() // Wait for the reducers to finish aggregating results
+	<-collectorDone
+	if prog != nil {
+		prog.finish()
+	}
+
+	// Per-input order depends on worker scheduling; sort for stable output
+	sort.Slice(finalResult.Files, func(i, j int) bool {
+		return finalResult.Files[i].Name < finalResult.Files[j].Name
+	})
+
+	if spillFinal {
+		return reducerRuns, finalResult, errors.Join(failures...)
+	}
+
+	// Reducers own disjoint key ranges, so combining their maps is a
+	// plain concatenation
+	total := 0
+	for _, m := range reducerMaps {
+		total += len(m)
+	}
+	finalResult.Counts = make(map[string]int, total)
+	for _, m := range reducerMaps {
+		for k, v := range m {
+			finalResult.Counts[k] = v
+		}
+	}
+	return nil, finalResult, errors.Join(failures...)
+}
+
+// processInput waits for inputs on the workQueue, processes each one,
+// and sends the word count results to the result channel.
+func processInput(ctx context.Context, wg *sync.WaitGroup, reduceChans []chan map[string]int, fileResults chan<- FileResult, inputErrs chan<- error, workQueue <-chan Input, opts Options, prog *progressTracker, limiter *rateLimiter) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				log.Errorf("Worker panicked: %v", r)
+			}
+		}()
+
+		for in := range workQueue {
+			metrics.queueDepth.Add(-1)
+			if ctx.Err() != nil {
+				// Cancelled: drain the queue without opening anything
+				continue
+			}
+			_, span := tracer.Start(ctx, "wordcount.map",
+				trace.WithAttributes(attribute.String("input", in.Name)))
+			busyStart := time.Now()
+			rc, err := in.Open()
+			if err != nil {
+				span.RecordError(err)
+				span.End()
+				metrics.inputErrors.Add(1)
+				inputErrs <- &InputError{Name: in.Name, Err: err}
+				continue
+			}
+
+			var src io.Reader = rc
+			if prog != nil {
+				src = prog.reader(rc)
+			}
+			if limiter != nil {
+				// Throttle raw input bytes, before decompression
+				src = &throttledReader{r: src, l: limiter}
+			}
+			src = &countingReader{r: src, n: &metrics.bytesRead}
+			if ctx.Done() != nil {
+				// Cancellation interrupts mid-file at the next read
+				src = &contextReader{ctx: ctx, r: src}
+			}
+			r, err := maybeDecompress(in.Name, src)
+			if err == nil && opts.Extract != ExtractPDF {
+				var binary bool
+				if r, binary = sniffBinary(r); binary {
+					// Counting "words" in an executable or image wastes
+					// CPU and pollutes the totals with garbage tokens
+					log.Warnf("Skipping binary file: %s", in.Name)
+					span.SetAttributes(attribute.Bool("binary", true))
+					span.End()
+					rc.Close()
+					if prog != nil {
+						prog.fileDone()
+					}
+					continue
+				}
+				// PDF extraction must see the raw bytes; everything
+				// else is text that may need converting to UTF-8
+				r, err = transcodeReader(r, opts)
+				if err == nil {
+					r, err = newUTF8Sanitizer(r, in.Name, opts.InvalidUTF8)
+				}
+				if err == nil && (opts.Normalize != "" || opts.FoldDiacritics) {
+					r, err = normalizeReader(r, opts)
+				}
+			}
+			if err == nil {
+				r, err = extractReader(r, opts)
+			}
+			if err == nil && opts.CSVColumn != "" {
+				r = newCSVColumnReader(r, opts.CSVColumn)
+			}
+			if err == nil && opts.JSONField != "" {
+				r = newJSONFieldReader(r, opts.JSONField)
+			}
+			if err != nil {
+				span.RecordError(err)
+				span.End()
+				metrics.inputErrors.Add(1)
+				inputErrs <- &InputError{Name: in.Name, Err: err}
+				rc.Close()
+				continue
+			}
+
+			res := make(map[string]int)
+			flushed := 0
+			var flush func(map[string]int)
+			if (opts.CombinerFlush > 0 || opts.CombinerFlushBytes > 0) && !opts.PerFile && opts.Mapper == nil && opts.Sketch == nil && opts.Distinct == nil {
+				// The combiner ships partials to the reducers mid-file;
+				// PerFile needs the complete map, so it opts out
+				flush = func(part map[string]int) {
+					for _, v := range part {
+						flushed += v
+					}
+					shuffleCounts(part, reduceChans)
+				}
+			}
+			if opts.Mapper != nil {
+				if err := opts.Mapper.Map(in.Name, r, func(word string, count int) { res[word] += count }); err != nil {
+					log.Warnf("Plugin mapper failed on %s: %v", in.Name, err)
+				}
+			} else {
+				countWords(in.Name, r, res, opts, flush)
+			}
+
+			if err := rc.Close(); err != nil {
+				log.Warnf("Error closing %s: %v", in.Name, err)
+			}
+
+			// Shuffle: split the counts into one partition per reducer
+			shuffleCounts(res, reduceChans)
+			if opts.PerFile {
+				fileResults <- FileResult{Name: in.Name, Counts: res}
+			}
+			if prog != nil {
+				prog.fileDone()
+			}
+			words := flushed
+			for _, v := range res {
+				words += v
+			}
+			metrics.wordsCounted.Add(int64(words))
+			metrics.filesProcessed.Add(1)
+			metrics.workerBusyNS.Add(int64(time.Since(busyStart)))
+			span.SetAttributes(attribute.Int("words", words))
+			span.End()
+			log.Infof("Processed: %s", in.Name) // Log progress
+		}
+	}()
+}
+
+// contextReader aborts reads once its context is cancelled, so a worker
+// in the middle of a huge (or never-ending) input stops at the next read
+// instead of running to end of stream.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
+// maybeDecompress wraps r in gzip decompression when the input looks
+// compressed, detected by the gzip magic bytes rather than the file name
+// alone so renamed or extensionless compressed files still work.
+func maybeDecompress(name string, r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(2)
+	if err != nil {
+		// Too short to be gzip (or unreadable); count it as-is
+		return br, nil
+	}
+	if magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		log.Debugf("Decompressing gzip input: %s", name)
+		return gz, nil
+	}
+	return br, nil
+}
+
+// countWords streams r through the word tokenizer and accumulates counts
+// into res. Tokenization is boundary-safe: a word or multi-byte rune that
+// straddles a read chunk is carried over rather than split in two. A
+// non-nil flush enables the combiner: whenever the in-flight counts cross
+// the CombinerFlush entry or byte threshold they are handed to flush and
+// counting restarts on a fresh map, with only the remainder reaching res.
+func countWords(name string, r io.Reader, res map[string]int, opts Options, flush func(map[string]int)) {
+	scanner := bufio.NewScanner(r)
+	buf := getBuf(opts.BufSize)
+	defer putBuf(buf)
+	scanner.Buffer(buf, opts.BufSize)
+	uax29 := opts.Segmenter == SegmenterUAX29
+	switch {
+	case opts.Mode == ModeChars:
+		uax29 = false
+		scanner.Split(bufio.ScanRunes)
+	case opts.CountRegex != nil:
+		uax29 = false
+		scanner.Split(scanRegexTokens(opts.CountRegex))
+	case opts.TokenRegex != nil:
+		uax29 = false
+		scanner.Split(scanRegexTokens(opts.TokenRegex))
+	case uax29:
+		scanner.Split(words.SplitFunc)
+	default:
+		keepHyphens := opts.Hyphens == HyphenKeep || opts.Hyphens == HyphenBoth
+		scanner.Split(scanWordRuns(opts.IncludeDigits, opts.KeepApostrophes || opts.ExpandContractions, keepHyphens))
+	}
+
+	// A locale-aware caser replaces strings.ToLower when a locale is
+	// set; Casers are stateful, so each mapper builds its own
+	var caser *cases.Caser
+	if !opts.CaseSensitive && opts.Locale != "" {
+		c := cases.Lower(language.Make(opts.Locale))
+		caser = &c
+	}
+
+	// Counting normally runs on the specialized open-addressing table;
+	// -stdlib-map swaps the pointer-valued Go map back in for
+	// comparison. Either way a key string is only allocated the first
+	// time a word is seen, which is what makes the byte-slice path
+	// below zero-copy
+	sketch := opts.Sketch
+	var hll *HyperLogLog
+	var table *countTable
+	var counts map[string]*int
+	switch {
+	case opts.Distinct != nil:
+		// Cardinality-only mode: tokens are hashed into a per-file
+		// sketch and merged into the run total when the file is done
+		hll = NewHyperLogLog()
+		defer func() { opts.Distinct.add(name, hll) }()
+	case sketch != nil:
+		// Approximate mode: every token lands in the shared sketch and
+		// no per-file state is kept at all
+	case opts.StdlibMap:
+		counts = make(map[string]*int)
+	default:
+		table = newCountTable()
+	}
+	var combinedBytes int64 // approximate count-state size for the combiner
+	bump := func(word string) {
+		if hll != nil {
+			hll.AddHash(fnv64a(word))
+			return
+		}
+		if sketch != nil {
+			sketch.Add(word)
+			return
+		}
+		if table != nil {
+			if table.inc(word) {
+				combinedBytes += int64(len(word)) + spillEntryOverhead
+			}
+			return
+		}
+		if p, ok := counts[word]; ok {
+			*p++
+			return
+		}
+		n := 1
+		counts[word] = &n
+		combinedBytes += int64(len(word)) + spillEntryOverhead
+	}
+
+	// window holds the trailing tokens for n-gram assembly or
+	// co-occurrence pairing
+	var window []string
+
+	// finish counts an already-normalized, already-filtered token,
+	// applying stemming and n-gram or co-occurrence assembly
+	finish := func(word string) {
+		if lemma, ok := opts.Lemmas[word]; ok {
+			word = lemma
+		}
+		if opts.Stem != "" {
+			if stemmed, err := snowball.Stem(word, stemLanguage(opts.Stem), false); err == nil {
+				word = stemmed
+			}
+		}
+		if opts.Mode == ModeLengths {
+			// The histogram keys are decimal lengths; the report turns
+			// them back into numbers
+			bump(strconv.Itoa(utf8.RuneCountInString(word)))
+			return
+		}
+		if opts.CoWindow > 0 {
+			// Pair the token with every token at distance 1..CoWindow
+			// behind it; the pair key is alphabetical so "a b" and
+			// "b a" accumulate together
+			for _, prev := range window {
+				a, b := prev, word
+				if b < a {
+					a, b = b, a
+				}
+				bump(a + " " + b)
+			}
+			window = append(window, word)
+			if len(window) > opts.CoWindow {
+				window = window[1:]
+			}
+			return
+		}
+		if opts.NGram > 1 {
+			window = append(window, word)
+			if len(window) > opts.NGram {
+				window = window[1:]
+			}
+			if len(window) == opts.NGram {
+				bump(strings.Join(window, " "))
+			}
+			return
+		}
+		bump(word)
+	}
+
+	// accept filters an already-folded token and hands survivors to finish
+	accept := func(word string) {
+		if opts.MinLen > 0 || opts.MaxLen > 0 {
+			n := utf8.RuneCountInString(word)
+			if n < opts.MinLen || (opts.MaxLen > 0 && n > opts.MaxLen) {
+				return
+			}
+		}
+		if _, drop := opts.Stopwords[word]; drop {
+			return
+		}
+		finish(word)
+	}
+
+	// addToken applies normalization and filters before counting a token
+	addToken := func(word string) {
+		if !opts.CaseSensitive {
+			// Convert to lowercase for case-insensitive comparison
+			if caser != nil {
+				word = caser.String(word)
+			} else {
+				word = strings.ToLower(word)
+			}
+		}
+		if opts.ExpandContractions {
+			key := word
+			if strings.ContainsRune(key, '’') {
+				// The table is keyed on straight apostrophes
+				key = strings.ReplaceAll(key, "’", "'")
+			}
+			if exp, ok := contractions[key]; ok {
+				// Each expanded word runs through the filters on its
+				// own, so stopword lists still drop "not" or "is"
+				for _, part := range strings.Split(exp, " ") {
+					accept(part)
+				}
+				return
+			}
+		}
+		if opts.Hyphens == HyphenBoth && strings.ContainsAny(word, hyphenRunes) {
+			accept(word)
+			for _, part := range strings.FieldsFunc(word, isHyphen) {
+				accept(part)
+			}
+			return
+		}
+		accept(word)
+	}
+
+	// lowerBuf is reused across tokens to lowercase without allocating
+	var lowerBuf []byte
+
+	// addBytes is the zero-copy counterpart of addToken: it normalizes
+	// and filters the raw token bytes in place, relying on the compiler's
+	// alloc-free map[string(b)] lookups, and converts to a string only
+	// when a new word enters the map (or when stemming/n-grams need one)
+	addBytes := func(tok []byte) {
+		if caser != nil {
+			// Locale folding allocates anyway; take the string path
+			addToken(string(tok))
+			return
+		}
+		if opts.ExpandContractions && bytes.ContainsAny(tok, "'’") {
+			// Only apostrophe tokens can be contractions; the rest stay
+			// on the zero-copy path
+			addToken(string(tok))
+			return
+		}
+		if opts.Hyphens == HyphenBoth && bytes.ContainsAny(tok, hyphenRunes) {
+			// Compounds need the split-and-count path
+			addToken(string(tok))
+			return
+		}
+		if !opts.CaseSensitive {
+			lowerBuf = lowerAppend(lowerBuf[:0], tok)
+			tok = lowerBuf
+		}
+		if opts.MinLen > 0 || opts.MaxLen > 0 {
+			n := utf8.RuneCount(tok)
+			if n < opts.MinLen || (opts.MaxLen > 0 && n > opts.MaxLen) {
+				return
+			}
+		}
+		if _, drop := opts.Stopwords[string(tok)]; drop {
+			return
+		}
+		if opts.Stem != "" || opts.Lemmas != nil || opts.NGram > 1 || opts.CoWindow > 0 || opts.Mode == ModeLengths {
+			finish(string(tok))
+			return
+		}
+		if hll != nil {
+			hll.AddHash(fnv64aBytes(tok))
+			return
+		}
+		if sketch != nil {
+			sketch.AddBytes(tok)
+			return
+		}
+		if table != nil {
+			if table.incBytes(tok) {
+				combinedBytes += int64(len(tok)) + spillEntryOverhead
+			}
+			return
+		}
+		if p, ok := counts[string(tok)]; ok {
+			*p++
+			return
+		}
+		n := 1
+		counts[string(tok)] = &n
+		combinedBytes += int64(len(tok)) + spillEntryOverhead
+	}
+
+	for scanner.Scan() {
+		if flush != nil {
+			entries := len(counts)
+			if table != nil {
+				entries = table.used
+			}
+			if (opts.CombinerFlush > 0 && entries >= opts.CombinerFlush) ||
+				(opts.CombinerFlushBytes > 0 && combinedBytes >= opts.CombinerFlushBytes) {
+				var part map[string]int
+				if table != nil {
+					part = table.toMap()
+					table = newCountTable()
+				} else {
+					part = make(map[string]int, len(counts))
+					for w, p := range counts {
+						part[w] = *p
+					}
+					counts = make(map[string]*int)
+				}
+				flush(part)
+				combinedBytes = 0
+			}
+		}
+		if opts.CountRegex != nil {
+			// Raw matches: no folding, no filters — the pattern already
+			// says exactly what should be counted
+			tok := scanner.Bytes()
+			if hll != nil {
+				hll.AddHash(fnv64aBytes(tok))
+				continue
+			}
+			if sketch != nil {
+				sketch.AddBytes(tok)
+				continue
+			}
+			if table != nil {
+				table.incBytes(tok)
+				continue
+			}
+			if p, ok := counts[string(tok)]; ok {
+				*p++
+			} else {
+				n := 1
+				counts[string(tok)] = &n
+			}
+			continue
+		}
+		if opts.Mode == ModeChars {
+			// Every non-whitespace code point is a "word" of its own
+			if r, _ := utf8.DecodeRune(scanner.Bytes()); unicode.IsSpace(r) {
+				continue
+			}
+			addBytes(scanner.Bytes())
+			continue
+		}
+		if uax29 && !wordlike(scanner.Bytes()) {
+			// UAX #29 emits every segment, including whitespace and
+			// punctuation runs; only keep word-like tokens
+			continue
+		}
+		if opts.Segmenter == SegmenterCJK && opts.TokenRegex == nil {
+			for _, sub := range splitCJK(scanner.Text()) {
+				addToken(sub)
+			}
+			continue
+		}
+		addBytes(scanner.Bytes())
+	}
+	if err := scanner.Err(); err != nil {
+		log.Warnf("Error reading %s: %v", name, err)
+	}
+
+	if table != nil {
+		table.drainInto(res)
+		return
+	}
+	for word, p := range counts {
+		res[word] += *p
+	}
+}
+
+// shuffleCounts splits a partial count map into one partition per
+// reducer by key hash and sends each non-empty partition down its
+// channel.
+func shuffleCounts(res map[string]int, reduceChans []chan map[string]int) {
+	parts := make([]map[string]int, len(reduceChans))
+	for k, v := range res {
+		// Partition in unsigned space: on 32-bit platforms int(hash)
+		// goes negative for half of all hashes and would index out of
+		// range
+		p := int(fnv32a(k) % uint32(len(reduceChans)))
+		if parts[p] == nil {
+			parts[p] = make(map[string]int)
+		}
+		parts[p][k] = v
+	}
+	for i, part := range parts {
+		if part != nil {
+			reduceChans[i] <- part
+		}
+	}
+}
+
+// lowerAppend appends the lowercase form of src to dst and returns the
+// extended slice. ASCII bytes take the fast path; multi-byte runes go
+// through unicode.ToLower, matching strings.ToLower semantics without a
+// per-token allocation.
+func lowerAppend(dst, src []byte) []byte {
+	for i := 0; i < len(src); {
+		b := src[i]
+		if b < utf8.RuneSelf {
+			if 'A' <= b && b <= 'Z' {
+				b += 'a' - 'A'
+			}
+			dst = append(dst, b)
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRune(src[i:])
+		dst = utf8.AppendRune(dst, unicode.ToLower(r))
+		i += size
+	}
+	return dst
+}
+
+// regexCarry is how many trailing bytes scanRegexTokens keeps un-consumed
+// when no match is found, so a token straddling a read boundary is still
+// matched once more data arrives.
+const regexCarry = 64 * 1024
+
+// scanRegexTokens returns a bufio.SplitFunc that emits every match of re
+// as a token. A match touching the end of the buffer is deferred until
+// more data arrives so tokens cannot be cut at read boundaries.
+func scanRegexTokens(re *regexp.Regexp) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		loc := re.FindIndex(data)
+		if loc == nil {
+			if atEOF {
+				return len(data), nil, nil
+			}
+			// Discard all but a bounded tail, in case a token is
+			// split across the boundary
+			if len(data) > regexCarry {
+				return len(data) - regexCarry, nil, nil
+			}
+			return 0, nil, nil
+		}
+		if loc[1] == len(data) && !atEOF {
+			// The match may continue in the next chunk; consume up to
+			// its start and ask for more data
+			return loc[0], nil, nil
+		}
+		return loc[1], data[loc[0]:loc[1]], nil
+	}
+}
+
+// stemLanguage maps short language codes to the names the snowball
+// package expects; full names pass through unchanged.
+func stemLanguage(lang string) string {
+	switch lang {
+	case "en":
+		return "english"
+	case "es":
+		return "spanish"
+	case "fr":
+		return "french"
+	case "ru":
+		return "russian"
+	case "sv":
+		return "swedish"
+	case "no":
+		return "norwegian"
+	case "hu":
+		return "hungarian"
+	}
+	return lang
+}
+
+// splitCJK breaks a mixed token into subtokens: runs of CJK ideographs
+// become overlapping character bigrams (or a single character when the run
+// has length one), and everything else passes through as one token.
+func splitCJK(token string) []string {
+	runes := []rune(token)
+	var out []string
+	for i := 0; i < len(runes); {
+		if !isCJK(runes[i]) {
+			j := i
+			for j < len(runes) && !isCJK(runes[j]) {
+				j++
+			}
+			out = append(out, string(runes[i:j]))
+			i = j
+			continue
+		}
+		j := i
+		for j < len(runes) && isCJK(runes[j]) {
+			j++
+		}
+		if j-i == 1 {
+			out = append(out, string(runes[i:j]))
+		} else {
+			for k := i; k+2 <= j; k++ {
+				out = append(out, string(runes[k:k+2]))
+			}
+		}
+		i = j
+	}
+	return out
+}
+
+// isCJK reports whether the rune belongs to a space-less East Asian script.
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r)
+}
+
+// wordlike reports whether a UAX #29 segment contains at least one letter
+// or digit, filtering out whitespace and punctuation segments.
+func wordlike(token []byte) bool {
+	for i := 0; i < len(token); {
+		r, width := utf8.DecodeRune(token[i:])
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return true
+		}
+		i += width
+	}
+	return false
+}
+
+// hyphenRunes are the characters Options.Hyphens treats as hyphens.
+const hyphenRunes = "-‐‑"
+
+// isHyphen reports whether r is one of hyphenRunes.
+func isHyphen(r rune) bool { return r == '-' || r == '‐' || r == '‑' }
+
+// scanWordRuns returns a bufio.SplitFunc that emits maximal runs of word
+// characters as tokens, skipping everything else. Word characters are
+// Unicode letters, plus digits when includeDigits is set, plus apostrophes
+// and hyphens when keepApostrophes or keepHyphens is set (trimmed from
+// token edges, so only internal ones survive). Unlike splitting each read
+// chunk independently, it asks the scanner for more data when a token (or
+// a multi-byte rune) may continue past the end of the buffer, so counts do
+// not depend on the read buffer size.
+func scanWordRuns(includeDigits, keepApostrophes, keepHyphens bool) bufio.SplitFunc {
+	isWord := unicode.IsLetter
+	if includeDigits {
+		isWord = func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+	}
+	// Joiners extend a run but never start one, so "don't" and
+	// "state-of-the-art" hold together while a quote or dash before a
+	// word does not
+	inRun := isWord
+	switch {
+	case keepApostrophes && keepHyphens:
+		inRun = func(r rune) bool { return isWord(r) || r == '\'' || r == '’' || isHyphen(r) }
+	case keepApostrophes:
+		inRun = func(r rune) bool { return isWord(r) || r == '\'' || r == '’' }
+	case keepHyphens:
+		inRun = func(r rune) bool { return isWord(r) || isHyphen(r) }
+	}
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		// Skip leading non-word bytes
+		start := 0
+		for start < len(data) {
+			r, width := utf8.DecodeRune(data[start:])
+			if r == utf8.RuneError && width == 1 && !atEOF && start+utf8.UTFMax > len(data) {
+				// Possibly a partial rune at the buffer end; ask for more data
+				return start, nil, nil
+			}
+			if isWord(r) {
+				break
+			}
+			start += width
+		}
+
+		// Scan until the end of the word run
+		for i := start; i < len(data); {
+			r, width := utf8.DecodeRune(data[i:])
+			if r == utf8.RuneError && width == 1 && !atEOF && i+utf8.UTFMax > len(data) {
+				return start, nil, nil
+			}
+			if !inRun(r) {
+				return i, bytes.TrimRight(data[start:i], "'’"+hyphenRunes), nil
+			}
+			i += width
+		}
+
+		// At EOF a trailing word run is a complete token; otherwise
+		// request more data in case it continues in the next chunk
+		if atEOF && len(data) > start {
+			return len(data), bytes.TrimRight(data[start:], "'’"+hyphenRunes), nil
+		}
+		return start, nil, nil
+	}
+}
+
+// PrintResult prints the final word count results in a tabular format.
+func PrintResult(result map[string]int) {
+	fmt.Printf("%-10s%s\n", "Count", "Word")
+	fmt.Printf("%-10s%s\n", "-----", "----")
+
+	for w, c := range result {
+		fmt.Printf("%-10v%s\n", c, w)
+	}
+}
+
+// reducer aggregates the partitions routed to it into a private map and
+// publishes the map into its own slot when its channel closes. Keys never
+// appear in more than one partition, so no synchronization beyond the
+// WaitGroup is needed. When a memory budget is set and exceeded, the map
+// is spilled to disk as a sorted run and counting restarts on a fresh map;
+// spilled runs are folded back in after the last partition arrives —
+// unless spillFinal is set, in which case the remaining map is written out
+// as one more sorted run for the caller's external merge.
+func reducer(ctx context.Context, wg *sync.WaitGroup, reducerMaps []map[string]int, reducerRuns [][]string, in <-chan map[string]int, slot int, memBudget int64, spillFinal bool, red Reducer) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, span := tracer.Start(ctx, "wordcount.reduce",
+			trace.WithAttributes(attribute.Int("partition", slot)))
+		defer span.End()
+		m := make(map[string]int)
+		var memUsed int64
+		var spills []string
+		for part := range in {
+			if red != nil {
+				// Custom aggregation; the memory budget only
+				// understands plain summation, so spilling is skipped
+				red.Reduce(m, part)
+				continue
+			}
+			for k, v := range part {
+				if _, seen := m[k]; !seen {
+					memUsed += int64(len(k)) + spillEntryOverhead
+				}
+				m[k] += v
+			}
+			if memBudget > 0 && memUsed > memBudget {
+				path, err := writeSpillRun(m)
+				if err != nil {
+					log.Warnf("Failed to spill counts to disk: %v", err)
+					continue // Keep going in memory rather than lose counts
+				}
+				spills = append(spills, path)
+				m = make(map[string]int)
+				memUsed = 0
+			}
+		}
+
+		if spillFinal {
+			// Hand all runs (plus the in-memory remainder) to the
+			// external merge instead of rebuilding a map
+			if len(m) > 0 {
+				path, err := writeSpillRun(m)
+				if err != nil {
+					log.Errorf("Failed to spill final counts: %v", err)
+				} else {
+					spills = append(spills, path)
+				}
+			}
+			reducerRuns[slot] = spills
+			return
+		}
+
+		// Fold the spilled runs back into the final map for this partition
+		for _, path := range spills {
+			err := readSpillRun(path, func(word string, count int) error {
+				m[word] += count
+				return nil
+			})
+			if err != nil {
+				log.Errorf("Failed to read spill file %s: %v", path, err)
+			}
+			os.Remove(path)
+		}
+		reducerMaps[slot] = m
+	}()
+}
+
+// fnv32a hashes a string with FNV-1a, inlined to avoid the allocation of
+// a hash.Hash32 per key.
+func fnv32a(s string) uint32 {
+	const (
+		offset32 = 2166136261
+		prime32  = 16777619
+	)
+	h := uint32(offset32)
+	for i := 0; i < len(s); i++ {
+		h ^= uint32(s[i])
+		h *= prime32
+	}
+	return h
+}
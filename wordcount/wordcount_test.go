@@ -1,16 +1,18 @@
-package main
+package wordcount
 
 import (
 	"os"
 	"runtime"
+	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 func TestProcessFiles(t *testing.T) {
 	// Prepare temporary files to test
 	testFile1 := "testfile1.txt"
 	testFile2 := "testfile2.txt"
-        // These files will be removed later
+	// These files will be removed later
 
 	// Content to be stored in both the files
 	content := "apple orange! banana? apple.\n banana apple: apple. banana..."
@@ -26,7 +28,7 @@ func TestProcessFiles(t *testing.T) {
 	defer os.Remove(testFile2)
 	// Process the files
 	files := []string{testFile1, testFile2}
-	result, err := processFiles(files, runtime.NumCPU())
+	result, err := ProcessFiles(files, Options{Workers: runtime.NumCPU()})
 
 	if err != nil {
 		t.Fatalf("Error processing files: %v", err)
@@ -38,7 +40,7 @@ func TestProcessFiles(t *testing.T) {
 		"orange": 2,
 		"banana": 6,
 	}
-	printResult(result)
+	PrintResult(result)
 
 	for word, expectedCount := range expectedResult {
 		if count, found := result[word]; !found || count != expectedCount {
@@ -46,3 +48,21 @@ func TestProcessFiles(t *testing.T) {
 		}
 	}
 }
+
+func TestCountWordsChunkBoundary(t *testing.T) {
+	// Force one-byte reads so every word and every multi-byte rune
+	// straddles a read boundary; counts must not depend on chunking
+	content := "apple banana café apple café"
+	res := make(map[string]int)
+	countWords("test", iotest.OneByteReader(strings.NewReader(content)), res, Options{}.withDefaults(), nil)
+
+	expected := map[string]int{"apple": 2, "banana": 1, "café": 2}
+	for word, expectedCount := range expected {
+		if res[word] != expectedCount {
+			t.Errorf("For word '%s': expected count %d, but got %d", word, expectedCount, res[word])
+		}
+	}
+	if len(res) != len(expected) {
+		t.Errorf("Expected %d distinct words, got %d: %v", len(expected), len(res), res)
+	}
+}